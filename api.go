@@ -1,19 +1,42 @@
 package yno
 
 import (
+	"sync"
+	"time"
+
 	"github.com/murasame29/yno-sdk/client"
 )
 
 type YNOClient struct {
-	APIKey        string
+	APIKey string
+	// MngAPIVersion: 全てのリクエストに付与するAPIバージョン。空の場合は付与しない
 	MngAPIVersion string
-	client        *client.Client
+	// VersionCheck: サーバーのAPIバージョンが対応範囲外の場合の挙動
+	VersionCheck VersionCheck
+	// OnVersionMismatch: VersionCheckWarn でサーバーのAPIバージョンが対応範囲外だった場合に呼ばれる
+	OnVersionMismatch VersionMismatchHandler
+	// OnWarning: レスポンスに Warnings が含まれていた場合に呼ばれる
+	OnWarning WarningHandler
+	// StrictWarnings: true の場合、Warnings を含むレスポンスに対してレスポンスとともに *WarningError を返す
+	StrictWarnings bool
+	// DeviceStatsRange: DeviceStats が 1 回の GetDeviceStatistic で取得する期間。0 の場合は DeviceStatsMaxRange
+	DeviceStatsRange time.Duration
+	client           *client.Client
+
+	mu               sync.Mutex
+	serverAPIVersion string
+	routerLocks      keyedMutex
 }
 
 const YNO_BASE_URL = "https://yno-mngapi.netvolante.jp"
 
+const (
+	apiKeyHeader        = "X-Yamaha-YNO-MngAPI-Key"
+	mngAPIVersionHeader = "X-Yamaha-YNO-MngAPI-Version"
+)
+
 func NewClient(baseURL, apiKey string, opts ...client.Option) (*YNOClient, error) {
-	opts = append(opts, client.WithHeader("X-Yamaha-YNO-MngAPI-Key", apiKey))
+	opts = append(opts, client.WithHeader(apiKeyHeader, apiKey))
 
 	client, err := client.NewClient(baseURL, opts...)
 	if err != nil {
@@ -21,7 +44,17 @@ func NewClient(baseURL, apiKey string, opts ...client.Option) (*YNOClient, error
 	}
 
 	return &YNOClient{
-		APIKey: apiKey,
-		client: client,
+		APIKey:        apiKey,
+		MngAPIVersion: DefaultMngAPIVersion,
+		client:        client,
 	}, nil
 }
+
+// WithRateLimit は同じAPIキーを使う YNOClient 間で共有されるレートリミッターを設定する
+func WithRateLimit(rate float64, burst int) client.Option {
+	return client.WithSharedRateLimiter(apiKeyHeader, rate, burst)
+}
+
+func (c *YNOClient) RateLimiter() *client.RateLimiter {
+	return c.client.RateLimiter()
+}
@@ -0,0 +1,232 @@
+package yno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// UserRouterIndex はユーザーのアカウント名から、そのユーザーが割り当てられているルーターのシリアル番号への索引
+type UserRouterIndex map[string][]string
+
+// Routers は accountName が割り当てられているルーターのシリアル番号を返す
+func (idx UserRouterIndex) Routers(accountName string) []string {
+	return idx[accountName]
+}
+
+// Users は索引に含まれるユーザーのアカウント名を昇順で返す
+func (idx UserRouterIndex) Users() []string {
+	return slices.Sorted(maps.Keys(idx))
+}
+
+// BuildUserRouterIndex は routers の AssignedUsers からユーザーごとのルーターの索引を作成する
+func BuildUserRouterIndex(routers []RouterResponseRouter) UserRouterIndex {
+	idx := UserRouterIndex{}
+	for _, router := range routers {
+		for _, user := range router.AssignedUsers {
+			idx[user] = append(idx[user], router.SerialNumber)
+		}
+	}
+
+	for _, serialNumbers := range idx {
+		slices.Sort(serialNumbers)
+	}
+
+	return idx
+}
+
+// UserRouterIndex はテナント内の全てのルーターを検索し、ユーザーごとのルーターの索引を作成する
+func (c *YNOClient) UserRouterIndex(ctx context.Context, opts ...OptionFunc) (UserRouterIndex, error) {
+	routers, err := Collect(c.AllRouters(ctx, nil, opts...), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildUserRouterIndex(routers), nil
+}
+
+// UserAssignmentChange はルーター1台の AssignedUsers の変更内容
+type UserAssignmentChange struct {
+	SerialNumber string
+	Before       []string
+	After        []string
+	Added        []string
+	Removed      []string
+}
+
+// UserAssignmentPlan は SyncUserAssignments が行う UpdateRotuer の一覧
+type UserAssignmentPlan struct {
+	Changes []UserAssignmentChange
+}
+
+// Diff は計画を人が読める差分形式にする
+//
+//	RTX1300-001: +alice -bob
+func (p *UserAssignmentPlan) Diff() string {
+	var b strings.Builder
+	for _, change := range p.Changes {
+		b.WriteString(change.SerialNumber + ":")
+		for _, user := range change.Added {
+			b.WriteString(" +" + user)
+		}
+		for _, user := range change.Removed {
+			b.WriteString(" -" + user)
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// PlanUserAssignments は desired (アカウント名 → シリアル番号) の状態にするために必要な変更を routers から計算する
+// desired に含まれないユーザーの割り当ては変更しない
+// desired のユーザーの割り当ては desired のルーターのみになる
+func PlanUserAssignments(routers []RouterResponseRouter, desired map[string][]string) (*UserAssignmentPlan, error) {
+	known := make(map[string]bool, len(routers))
+	for _, router := range routers {
+		known[router.SerialNumber] = true
+	}
+
+	// シリアル番号 → そのルーターに割り当てたいユーザー
+	want := map[string][]string{}
+	for _, user := range slices.Sorted(maps.Keys(desired)) {
+		if user == "" {
+			return nil, ValidateErrorRequired{"AccountName"}
+		}
+
+		for _, serialNumber := range desired[user] {
+			if !known[serialNumber] {
+				return nil, fmt.Errorf("%w: %s (desired for %s)", ErrRouterNotFound, serialNumber, user)
+			}
+
+			if !slices.Contains(want[serialNumber], user) {
+				want[serialNumber] = append(want[serialNumber], user)
+			}
+		}
+	}
+
+	plan := &UserAssignmentPlan{}
+	for _, router := range routers {
+		change := UserAssignmentChange{
+			SerialNumber: router.SerialNumber,
+			Before:       router.AssignedUsers,
+		}
+
+		for _, user := range router.AssignedUsers {
+			_, managed := desired[user]
+			if managed && !slices.Contains(want[router.SerialNumber], user) {
+				change.Removed = append(change.Removed, user)
+				continue
+			}
+			change.After = append(change.After, user)
+		}
+
+		for _, user := range want[router.SerialNumber] {
+			if !slices.Contains(router.AssignedUsers, user) {
+				change.Added = append(change.Added, user)
+				change.After = append(change.After, user)
+			}
+		}
+
+		if len(change.Added) > 0 || len(change.Removed) > 0 {
+			plan.Changes = append(plan.Changes, change)
+		}
+	}
+
+	slices.SortFunc(plan.Changes, func(a, b UserAssignmentChange) int {
+		return strings.Compare(a.SerialNumber, b.SerialNumber)
+	})
+
+	return plan, nil
+}
+
+type SyncUserAssignmentsOptions struct {
+	// DryRun: true の場合は計画のみを返し、ルーターを更新しない
+	// 割り当てが空になり更新できない変更は、更新する場合と同じく Failed と ErrEmptyAssignment で返す
+	DryRun bool
+}
+
+// UserAssignmentSyncResult は SyncUserAssignments の結果
+type UserAssignmentSyncResult struct {
+	Plan *UserAssignmentPlan
+	// Updated: 更新したルーター (変更後)
+	Updated []RouterResponseRouter
+	// Failed: 更新に失敗したルーターのシリアル番号とエラー
+	Failed map[string]error
+}
+
+// SyncUserAssignments は desired (アカウント名 → シリアル番号) の状態になるよう、変更が必要なルーターのみを UpdateRotuer で更新する
+// 更新時にはルーターを読み直し、計画時点から変化した他のユーザーの割り当てを保持する
+func (c *YNOClient) SyncUserAssignments(ctx context.Context, desired map[string][]string, options *SyncUserAssignmentsOptions, opts ...OptionFunc) (*UserAssignmentSyncResult, error) {
+	if options == nil {
+		options = &SyncUserAssignmentsOptions{}
+	}
+
+	routers, err := Collect(c.AllRouters(ctx, nil, opts...), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := PlanUserAssignments(routers, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UserAssignmentSyncResult{Plan: plan, Failed: map[string]error{}}
+	if options.DryRun {
+		var errs []error
+		for _, change := range plan.Changes {
+			if len(change.After) == 0 {
+				err := emptyAssignmentError(change)
+				result.Failed[change.SerialNumber] = err
+				errs = append(errs, fmt.Errorf("%s: %w", change.SerialNumber, err))
+			}
+		}
+
+		return result, errors.Join(errs...)
+	}
+
+	var errs []error
+	for _, change := range plan.Changes {
+		updated, err := c.modifyRouter(ctx, change.SerialNumber, func(router *RouterResponseRouter) (*RouterAssignedObject, error) {
+			users := slices.DeleteFunc(slices.Clone(router.AssignedUsers), func(user string) bool {
+				return slices.Contains(change.Removed, user)
+			})
+			for _, user := range change.Added {
+				if !slices.Contains(users, user) {
+					users = append(users, user)
+				}
+			}
+
+			if slices.Equal(users, router.AssignedUsers) {
+				return nil, nil
+			}
+
+			if len(users) == 0 {
+				return nil, emptyAssignmentError(change)
+			}
+
+			return &RouterAssignedObject{AssignedUsers: users}, nil
+		}, opts...)
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+
+			result.Failed[change.SerialNumber] = err
+			errs = append(errs, fmt.Errorf("%s: %w", change.SerialNumber, err))
+			continue
+		}
+
+		result.Updated = append(result.Updated, *updated)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+func emptyAssignmentError(change UserAssignmentChange) error {
+	return fmt.Errorf("%w: removing users %v from %s", ErrEmptyAssignment, change.Removed, change.SerialNumber)
+}
@@ -0,0 +1,42 @@
+package yno_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/murasame29/yno-sdk"
+	"github.com/murasame29/yno-sdk/ynotest"
+)
+
+func TestSyncUserAssignmentsDryRunEmptyAssignment(t *testing.T) {
+	s := ynotest.NewServer(ynotest.WithRouters(
+		yno.RouterResponseRouter{SerialNumber: "S1", RouterAssignedObject: yno.RouterAssignedObject{AssignedUsers: []string{"alice"}}},
+		yno.RouterResponseRouter{SerialNumber: "S2", RouterAssignedObject: yno.RouterAssignedObject{AssignedUsers: []string{"bob"}}},
+	))
+	defer s.Close()
+
+	c, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// alice を S1 から S2 に移すと S1 の割り当てが空になる
+	desired := map[string][]string{"alice": {"S2"}}
+	result, err := c.SyncUserAssignments(context.Background(), desired, &yno.SyncUserAssignmentsOptions{DryRun: true})
+	if !errors.Is(err, yno.ErrEmptyAssignment) {
+		t.Fatalf("SyncUserAssignments() error = %v, want %v", err, yno.ErrEmptyAssignment)
+	}
+
+	if len(result.Plan.Changes) != 2 {
+		t.Errorf("Plan.Changes = %+v, want S1 and S2", result.Plan.Changes)
+	}
+
+	if _, ok := result.Failed["S1"]; !ok || len(result.Failed) != 1 {
+		t.Errorf("Failed = %v, want only S1", result.Failed)
+	}
+
+	if router, _ := s.Router("S2"); len(router.AssignedUsers) != 1 {
+		t.Errorf("dry run updated S2: %q", router.AssignedUsers)
+	}
+}
@@ -0,0 +1,156 @@
+package backup_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/murasame29/yno-sdk"
+	"github.com/murasame29/yno-sdk/backup"
+	"github.com/murasame29/yno-sdk/ynotest"
+)
+
+const savedConfig = `ip route default gateway pp 1
+ip lan1 address 192.168.100.1/24
+pp select 1
+ pppoe use lan2
+ pp enable 1
+`
+
+func TestRunnerExportRestore(t *testing.T) {
+	current := `# Reporting Date: Jan 1 00:00:00 2024
+ip route default gateway pp 1
+ip lan1 address 192.168.200.1/24
+dns server 8.8.8.8
+pp select 1
+ pppoe use lan2
+ ip pp mtu 1454
+ pp enable 1
+`
+	s := ynotest.NewServer(
+		ynotest.WithRouters(yno.RouterResponseRouter{SerialNumber: "S1"}),
+		ynotest.WithCommandHandler(func(serialNumber, command string) ([]string, yno.ExitCode) {
+			return strings.Split(current, "\n"), yno.ExitCodeSuccess
+		}),
+	)
+	defer s.Close()
+
+	c, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := backup.NewDirStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := store.Save(context.Background(), "S1", savedConfig, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	runner, err := backup.NewRunner(c, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := runner.ExportRestore(context.Background(), "S1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"no dns server 8.8.8.8",
+		"ip lan1 address 192.168.100.1/24",
+		"pp select 1",
+		"no ip pp mtu 1454",
+		"pp select none",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ExportRestore() = %q, want %q", got, want)
+	}
+}
+
+func TestRestoreCommandsSplitIntoTasks(t *testing.T) {
+	var b strings.Builder
+	for i := range yno.MaxTaskCommands - 1 {
+		fmt.Fprintf(&b, "ip filter %d pass * * * * *\n", i+1)
+	}
+	b.WriteString("pp select 1\n pppoe use lan2\n pp enable 1\n")
+
+	commands, err := backup.RestoreCommands("", b.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commands) <= yno.MaxTaskCommands {
+		t.Fatalf("RestoreCommands() returned %d commands, want more than %d", len(commands), yno.MaxTaskCommands)
+	}
+
+	var executed []string
+	s := ynotest.NewServer(
+		ynotest.WithRouters(yno.RouterResponseRouter{SerialNumber: "S1"}),
+		ynotest.WithCommandHandler(func(serialNumber, command string) ([]string, yno.ExitCode) {
+			executed = append(executed, command)
+			return nil, yno.ExitCodeSuccess
+		}),
+	)
+	defer s.Close()
+
+	c, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fleet, err := c.RunCommandsOnFleet(context.Background(), []string{"S1"}, commands, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fleet.Tasks) != 2 {
+		t.Fatalf("RunCommandsOnFleet() ran %d tasks, want 2", len(fleet.Tasks))
+	}
+
+	// 2つ目のタスクは pp select 1 を実行し直してから続きを実行する
+	want := []string{"pp select 1", "pppoe use lan2", "pp enable 1", "pp select none"}
+	if got := fleet.Tasks[1].Commands; !slices.Equal(got, want) {
+		t.Errorf("second task commands = %q, want %q", got, want)
+	}
+	if len(executed) != len(commands)+1 {
+		t.Errorf("executed %d commands, want %d", len(executed), len(commands)+1)
+	}
+}
+
+func TestNewRunnerGitStoreRetention(t *testing.T) {
+	store, err := backup.NewGitStore(t.TempDir())
+	if err != nil {
+		t.Skip(err)
+	}
+
+	if _, err := backup.NewRunner(nil, store); err != nil {
+		t.Errorf("NewRunner() without retention error = %v", err)
+	}
+
+	_, err = backup.NewRunner(nil, store, backup.WithRetention(backup.RetentionPolicy{KeepLast: 1}))
+	if !errors.Is(err, backup.ErrRetentionNotSupported) {
+		t.Errorf("NewRunner() error = %v, want %v", err, backup.ErrRetentionNotSupported)
+	}
+}
+
+func TestGitStorePrune(t *testing.T) {
+	store, err := backup.NewGitStore(t.TempDir())
+	if err != nil {
+		t.Skip(err)
+	}
+
+	if _, err := store.Prune(context.Background(), "S1", backup.RetentionPolicy{}); err != nil {
+		t.Errorf("Prune() with no policy error = %v", err)
+	}
+
+	_, err = store.Prune(context.Background(), "S1", backup.RetentionPolicy{KeepLast: 1})
+	if !errors.Is(err, backup.ErrRetentionNotSupported) {
+		t.Errorf("Prune() error = %v, want %v", err, backup.ErrRetentionNotSupported)
+	}
+}
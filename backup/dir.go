@@ -0,0 +1,163 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const versionTimeFormat = "20060102T150405Z"
+
+// DirStore はディレクトリに <シリアル番号>/<UTC時刻>.conf として版を保存する
+type DirStore struct {
+	dir string
+}
+
+// NewDirStore は dir に保存する DirStore を作成する。dir が存在しない場合は作成する
+func NewDirStore(dir string) (*DirStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &DirStore{dir: dir}, nil
+}
+
+func (s *DirStore) Save(ctx context.Context, serialNumber, config string, at time.Time) (Version, bool, error) {
+	if err := validateSerialNumber(serialNumber); err != nil {
+		return Version{}, false, err
+	}
+
+	versions, err := s.Versions(ctx, serialNumber)
+	if err != nil {
+		return Version{}, false, err
+	}
+
+	if len(versions) > 0 {
+		latest, err := s.Load(ctx, serialNumber, versions[0].ID)
+		if err != nil {
+			return Version{}, false, err
+		}
+		if latest == config {
+			return versions[0], false, nil
+		}
+	}
+
+	dir := filepath.Join(s.dir, serialNumber)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return Version{}, false, err
+	}
+
+	at = at.UTC().Truncate(time.Second)
+	v := Version{ID: at.Format(versionTimeFormat) + ".conf", Time: at}
+
+	// 書き込み途中のファイルを版として読まないよう、一時ファイルから置き換える
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return Version{}, false, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(config); err != nil {
+		tmp.Close()
+		return Version{}, false, err
+	}
+	if err := tmp.Close(); err != nil {
+		return Version{}, false, err
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, v.ID)); err != nil {
+		return Version{}, false, err
+	}
+
+	return v, true, nil
+}
+
+func (s *DirStore) Versions(ctx context.Context, serialNumber string) ([]Version, error) {
+	if err := validateSerialNumber(serialNumber); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(s.dir, serialNumber))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []Version
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".conf") {
+			continue
+		}
+
+		t, err := time.Parse(versionTimeFormat, strings.TrimSuffix(name, ".conf"))
+		if err != nil {
+			continue
+		}
+
+		versions = append(versions, Version{ID: name, Time: t})
+	}
+
+	sortVersions(versions)
+	return versions, nil
+}
+
+func (s *DirStore) Load(ctx context.Context, serialNumber, id string) (string, error) {
+	if id == "" {
+		versions, err := s.Versions(ctx, serialNumber)
+		if err != nil {
+			return "", err
+		}
+		if len(versions) == 0 {
+			return "", fmt.Errorf("%w: %s", ErrVersionNotFound, serialNumber)
+		}
+		id = versions[0].ID
+	}
+
+	if err := validateSerialNumber(serialNumber); err != nil {
+		return "", err
+	}
+	if id != filepath.Base(id) {
+		return "", fmt.Errorf("%w: %s %s", ErrVersionNotFound, serialNumber, id)
+	}
+
+	b, err := os.ReadFile(filepath.Join(s.dir, serialNumber, id))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: %s %s", ErrVersionNotFound, serialNumber, id)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func (s *DirStore) Prune(ctx context.Context, serialNumber string, policy RetentionPolicy) ([]Version, error) {
+	versions, err := s.Versions(ctx, serialNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	expired := policy.expired(versions, time.Now())
+	for _, v := range expired {
+		if err := os.Remove(filepath.Join(s.dir, serialNumber, v.ID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	return expired, nil
+}
+
+// validateSerialNumber はシリアル番号をファイル名として使えるかを検査する
+func validateSerialNumber(serialNumber string) error {
+	if serialNumber == "" || serialNumber != filepath.Base(serialNumber) || strings.HasPrefix(serialNumber, ".") {
+		return fmt.Errorf("backup: invalid serial number %q", serialNumber)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultGitAuthorName  = "yno backup"
+	defaultGitAuthorEmail = "yno-backup@localhost"
+	summaryLines          = 20
+)
+
+// GitStore はgitリポジトリに <シリアル番号>.conf として保存し、変更ごとにコミットする
+// コミットメッセージには変更された行の要約が入る
+// 版はgitの履歴として残し書き換えないため、RetentionPolicy には対応しない
+type GitStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewGitStore は dir のgitリポジトリに保存する GitStore を作成する
+// dir が存在しない場合やgitリポジトリでない場合は初期化する。git コマンドが必要
+func NewGitStore(dir string) (*GitStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	s := &GitStore{dir: dir}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); errors.Is(err, os.ErrNotExist) {
+		if _, err := s.git(context.Background(), "init", "--quiet"); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func (s *GitStore) Save(ctx context.Context, serialNumber, config string, at time.Time) (Version, bool, error) {
+	if err := validateSerialNumber(serialNumber); err != nil {
+		return Version{}, false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file := serialNumber + ".conf"
+	old, err := os.ReadFile(filepath.Join(s.dir, file))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Version{}, false, err
+	}
+
+	versions, err := s.versions(ctx, serialNumber)
+	if err != nil {
+		return Version{}, false, err
+	}
+
+	if len(versions) > 0 && string(old) == config {
+		return versions[0], false, nil
+	}
+
+	if err := os.WriteFile(filepath.Join(s.dir, file), []byte(config), 0o600); err != nil {
+		return Version{}, false, err
+	}
+
+	if _, err := s.git(ctx, "add", "--", file); err != nil {
+		return Version{}, false, err
+	}
+
+	message := serialNumber + ": initial backup"
+	if len(versions) > 0 {
+		message = serialNumber + ": " + Summary(string(old), config, summaryLines)
+		// 1行目を件名、変更された行を本文にする
+		message = strings.Replace(message, "\n", "\n\n", 1)
+	}
+
+	date := at.Format(time.RFC3339)
+	args := append(s.identity(ctx), "commit", "--quiet", "--date", date, "-m", message, "--", file)
+	if _, err := s.gitEnv(ctx, []string{"GIT_COMMITTER_DATE=" + date}, args...); err != nil {
+		return Version{}, false, err
+	}
+
+	hash, err := s.git(ctx, "rev-parse", "HEAD")
+	if err != nil {
+		return Version{}, false, err
+	}
+
+	return Version{ID: hash, Time: at.Truncate(time.Second)}, true, nil
+}
+
+func (s *GitStore) Versions(ctx context.Context, serialNumber string) ([]Version, error) {
+	if err := validateSerialNumber(serialNumber); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.versions(ctx, serialNumber)
+}
+
+func (s *GitStore) versions(ctx context.Context, serialNumber string) ([]Version, error) {
+	// コミットがまだない場合 git log は失敗する
+	if _, err := s.git(ctx, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		return nil, nil
+	}
+
+	out, err := s.git(ctx, "log", "--format=%H %cI", "--", serialNumber+".conf")
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []Version
+	for _, line := range strings.Split(out, "\n") {
+		hash, date, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			continue
+		}
+
+		versions = append(versions, Version{ID: hash, Time: t})
+	}
+
+	return versions, nil
+}
+
+func (s *GitStore) Load(ctx context.Context, serialNumber, id string) (string, error) {
+	if err := validateSerialNumber(serialNumber); err != nil {
+		return "", err
+	}
+
+	if id == "" {
+		id = "HEAD"
+	}
+
+	if strings.HasPrefix(id, "-") {
+		return "", fmt.Errorf("%w: %s %s", ErrVersionNotFound, serialNumber, id)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out, err := s.gitRaw(ctx, nil, "show", id+":"+serialNumber+".conf")
+	if err != nil {
+		return "", fmt.Errorf("%w: %s %s", ErrVersionNotFound, serialNumber, id)
+	}
+
+	return out, nil
+}
+
+// Prune はgitの履歴を書き換えないため、条件が指定された場合は ErrRetentionNotSupported を返す
+func (s *GitStore) Prune(ctx context.Context, serialNumber string, policy RetentionPolicy) ([]Version, error) {
+	if policy.enabled() {
+		return nil, fmt.Errorf("%w: GitStore keeps every version in the git history", ErrRetentionNotSupported)
+	}
+	return nil, nil
+}
+
+// identity はコミットする利用者が設定されていない場合に既定の名前とメールアドレスを指定する
+func (s *GitStore) identity(ctx context.Context) []string {
+	if email, _ := s.git(ctx, "config", "user.email"); email != "" {
+		return nil
+	}
+
+	return []string{"-c", "user.name=" + defaultGitAuthorName, "-c", "user.email=" + defaultGitAuthorEmail}
+}
+
+func (s *GitStore) git(ctx context.Context, args ...string) (string, error) {
+	return s.gitEnv(ctx, nil, args...)
+}
+
+func (s *GitStore) gitEnv(ctx context.Context, env []string, args ...string) (string, error) {
+	out, err := s.gitRaw(ctx, env, args...)
+	return strings.TrimSpace(out), err
+}
+
+func (s *GitStore) gitRaw(ctx context.Context, env []string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = s.dir
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
@@ -0,0 +1,131 @@
+// Package backup は show config をタスクで実行してルーターの設定をバックアップする
+//
+//	store, err := backup.NewDirStore("/var/backups/yno")
+//	runner, err := backup.NewRunner(client, store, backup.WithRetention(backup.RetentionPolicy{KeepLast: 30}))
+//	report, err := runner.Run(ctx, serialNumbers)
+//
+//	// 保存した版に戻すコマンド
+//	commands, err := runner.ExportRestore(ctx, serialNumber, "")
+package backup
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ShowConfigCommand はバックアップに使うコマンド
+const ShowConfigCommand = "show config"
+
+// volatilePrefixes は実行する度に変わるため保存前に取り除くコメント行
+var volatilePrefixes = []string{
+	"# Reporting Date:",
+	"# 出力日時:",
+}
+
+// NormalizeConfig は show config の出力を保存用に正規化する
+// 改行コードを LF に揃え、行末の空白と前後の空行、出力日時のコメント行を取り除き、末尾に改行を付ける
+func NormalizeConfig(output []string) string {
+	var lines []string
+	for _, chunk := range output {
+		chunk = strings.ReplaceAll(chunk, "\r\n", "\n")
+		for _, line := range strings.Split(chunk, "\n") {
+			line = strings.TrimRight(line, " \t\r")
+			if isVolatile(line) {
+				continue
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func isVolatile(line string) bool {
+	for _, prefix := range volatilePrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary は old から new への変更を "N lines added, M lines removed" の形式で表す
+// 変更された行は maxLines 行まで続けて "+ 行" "- 行" で示す
+func Summary(old, new string, maxLines int) string {
+	added, removed := lineChanges(old, new)
+	if len(added) == 0 && len(removed) == 0 {
+		return "no changes"
+	}
+
+	var b strings.Builder
+	b.WriteString(pluralLines(len(added)) + " added, " + pluralLines(len(removed)) + " removed")
+
+	n := 0
+	for _, change := range []struct {
+		sign  string
+		lines []string
+	}{{"-", removed}, {"+", added}} {
+		for _, line := range change.lines {
+			if n >= maxLines {
+				b.WriteString("\n...")
+				return b.String()
+			}
+			b.WriteString("\n" + change.sign + " " + line)
+			n++
+		}
+	}
+
+	return b.String()
+}
+
+// lineChanges は new にだけある行と old にだけある行を出現順に返す。同じ行が複数ある場合は回数で比較する
+func lineChanges(old, new string) (added, removed []string) {
+	count := map[string]int{}
+	for _, line := range configLines(old) {
+		count[line]++
+	}
+	for _, line := range configLines(new) {
+		if count[line] > 0 {
+			count[line]--
+			continue
+		}
+		added = append(added, line)
+	}
+
+	for _, line := range configLines(old) {
+		if count[line] > 0 {
+			count[line]--
+			removed = append(removed, line)
+		}
+	}
+
+	return added, removed
+}
+
+func configLines(config string) []string {
+	var lines []string
+	for _, line := range strings.Split(config, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func pluralLines(n int) string {
+	if n == 1 {
+		return "1 line"
+	}
+	return strconv.Itoa(n) + " lines"
+}
@@ -0,0 +1,49 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/murasame29/yno-sdk"
+	"github.com/murasame29/yno-sdk/rtxconfig"
+)
+
+// RestoreCommands は current の設定を保存した saved の設定に戻すコマンドの列を返す
+// saved にない行は no を付けて削除し、変更された行と追加された行を設定する (rtxconfig.Compare)
+// コマンドは yno.MaxTaskCommands を超えることがあるが、RunCommandsOnFleet で実行すると
+// タスクに分割され、選択中の pp select と tunnel select は次のタスクで実行し直される
+func RestoreCommands(current, saved string) ([]string, error) {
+	currentConfig, err := rtxconfig.Parse(current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse current config: %w", err)
+	}
+
+	savedConfig, err := rtxconfig.Parse(saved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse saved config: %w", err)
+	}
+
+	return rtxconfig.Compare(currentConfig, savedConfig).Commands(), nil
+}
+
+// ExportRestore は serialNumber のルーターで show config を実行して現在の設定を取得し、
+// Store に保存した版 id に戻すコマンドの列を返す。id が空の場合は最新の版を使う
+// 差分がない場合は空の列を返す
+func (r *Runner) ExportRestore(ctx context.Context, serialNumber, id string, opts ...yno.OptionFunc) ([]string, error) {
+	saved, err := r.store.Load(ctx, serialNumber, id)
+	if err != nil {
+		return nil, err
+	}
+
+	fleet, err := r.client.RunCommandsOnFleet(ctx, []string{serialNumber}, []string{ShowConfigCommand}, r.fleetOptions, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := showConfigOutput(fleet, serialNumber)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", serialNumber, err)
+	}
+
+	return RestoreCommands(current, saved)
+}
@@ -0,0 +1,174 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/murasame29/yno-sdk"
+)
+
+// Runner は show config をタスクで実行し、結果を Store に保存する
+type Runner struct {
+	client       *yno.YNOClient
+	store        Store
+	retention    RetentionPolicy
+	fleetOptions *yno.RunCommandsOnFleetOptions
+	now          func() time.Time
+}
+
+type Option func(*Runner)
+
+// WithRetention は保存後に古い版を削除する条件を設定する
+func WithRetention(policy RetentionPolicy) Option {
+	return func(r *Runner) {
+		r.retention = policy
+	}
+}
+
+// WithFleetOptions は show config のタスクを実行する際のオプションを設定する
+func WithFleetOptions(options *yno.RunCommandsOnFleetOptions) Option {
+	return func(r *Runner) {
+		r.fleetOptions = options
+	}
+}
+
+// NewRunner は store に保存する Runner を作成する
+// GitStore に WithRetention で条件を指定した場合は ErrRetentionNotSupported を返す
+func NewRunner(c *yno.YNOClient, store Store, opts ...Option) (*Runner, error) {
+	r := &Runner{
+		client: c,
+		store:  store,
+		now:    time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if _, ok := store.(*GitStore); ok && r.retention.enabled() {
+		return nil, fmt.Errorf("%w: GitStore keeps every version in the git history", ErrRetentionNotSupported)
+	}
+
+	return r, nil
+}
+
+// Result はルーター1台のバックアップ結果
+type Result struct {
+	SerialNumber string
+	// Version: 保存した版。変更がない場合は最新の版
+	Version Version
+	// Changed: 最新の版から変更があり新しい版を保存した場合 true
+	Changed bool
+	// Pruned: 保存条件によって削除した版
+	Pruned []Version
+	Err    error
+}
+
+// Report は Run の結果
+type Report struct {
+	Results []Result
+	// Tasks: show config を実行したタスク
+	Tasks []yno.FleetTask
+}
+
+// Changed は新しい版を保存したシリアル番号を返す
+func (r *Report) Changed() []string {
+	var changed []string
+	for _, result := range r.Results {
+		if result.Changed {
+			changed = append(changed, result.SerialNumber)
+		}
+	}
+	return changed
+}
+
+// Failed はバックアップに失敗したシリアル番号を返す
+func (r *Report) Failed() []string {
+	var failed []string
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result.SerialNumber)
+		}
+	}
+	return failed
+}
+
+// Run は serialNumbers のルーターで show config を実行し、設定を保存する
+// 一部のルーターで失敗しても残りのルーターは続け、全てのエラーをまとめて返す
+func (r *Runner) Run(ctx context.Context, serialNumbers []string, opts ...yno.OptionFunc) (*Report, error) {
+	fleet, err := r.client.RunCommandsOnFleet(ctx, serialNumbers, []string{ShowConfigCommand}, r.fleetOptions, opts...)
+	if fleet == nil {
+		return nil, err
+	}
+
+	report := &Report{Tasks: fleet.Tasks}
+	at := r.now()
+
+	var errs []error
+	for _, serialNumber := range uniqueSerialNumbers(serialNumbers) {
+		result := r.save(ctx, fleet, serialNumber, at)
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", serialNumber, result.Err))
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	return report, errors.Join(errs...)
+}
+
+func (r *Runner) save(ctx context.Context, fleet *yno.FleetResult, serialNumber string, at time.Time) Result {
+	result := Result{SerialNumber: serialNumber}
+
+	config, err := showConfigOutput(fleet, serialNumber)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Version, result.Changed, result.Err = r.store.Save(ctx, serialNumber, config, at)
+	if result.Err != nil {
+		return result
+	}
+
+	result.Pruned, result.Err = r.store.Prune(ctx, serialNumber, r.retention)
+	return result
+}
+
+// showConfigOutput は fleet から serialNumber の show config の出力を取り出して正規化する
+func showConfigOutput(fleet *yno.FleetResult, serialNumber string) (string, error) {
+	device, ok := fleet.Devices[serialNumber]
+	if !ok {
+		return "", errors.New("no task result")
+	}
+
+	cr, ok := device.Command(ShowConfigCommand)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", yno.ErrCommandNotFound, device.Status)
+	}
+
+	if cr.ExitCode != yno.ExitCodeSuccess {
+		return "", fmt.Errorf("command %q exited with %s", cr.Command, cr.ExitCode)
+	}
+
+	config := NormalizeConfig(cr.Output)
+	if config == "" {
+		return "", errors.New("empty config")
+	}
+
+	return config, nil
+}
+
+func uniqueSerialNumbers(serialNumbers []string) []string {
+	seen := make(map[string]struct{}, len(serialNumbers))
+	unique := make([]string, 0, len(serialNumbers))
+	for _, serialNumber := range serialNumbers {
+		if _, ok := seen[serialNumber]; ok {
+			continue
+		}
+		seen[serialNumber] = struct{}{}
+		unique = append(unique, serialNumber)
+	}
+	return unique
+}
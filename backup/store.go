@@ -0,0 +1,80 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+// ErrVersionNotFound は指定したバージョンのバックアップが存在しない場合に返される
+var ErrVersionNotFound = errors.New("backup: version not found")
+
+// ErrRetentionNotSupported は Store が RetentionPolicy による削除に対応していない場合に返される
+var ErrRetentionNotSupported = errors.New("backup: retention policy is not supported by the store")
+
+// Version は保存された設定の1つの版
+type Version struct {
+	// ID: ストア内で版を識別する値。DirStore ではファイル名、GitStore ではコミットハッシュ
+	ID   string
+	Time time.Time
+}
+
+// Store はシリアル番号ごとに設定を版として保存する
+type Store interface {
+	// Save は config を serialNumber の新しい版として保存する
+	// 最新の版と同じ内容の場合は保存せずに最新の版と false を返す
+	Save(ctx context.Context, serialNumber, config string, at time.Time) (Version, bool, error)
+	// Versions は serialNumber の版を新しい順に返す
+	Versions(ctx context.Context, serialNumber string) ([]Version, error)
+	// Load は serialNumber の版 id の設定を返す。id が空の場合は最新の版を返す
+	Load(ctx context.Context, serialNumber, id string) (string, error)
+	// Prune は policy に従って古い版を削除し、削除した版を返す
+	// policy に対応していない場合は ErrRetentionNotSupported を返す
+	Prune(ctx context.Context, serialNumber string, policy RetentionPolicy) ([]Version, error)
+}
+
+// RetentionPolicy は古い版を削除する条件
+// KeepLast と MaxAge のどちらかを満たす版は残し、最新の版は常に残す
+type RetentionPolicy struct {
+	// KeepLast: 新しい順に残す版の数。0 の場合はこの条件を使わない
+	KeepLast int
+	// MaxAge: この期間内に保存された版を残す。0 の場合はこの条件を使わない
+	MaxAge time.Duration
+}
+
+func (p RetentionPolicy) enabled() bool {
+	return p.KeepLast > 0 || p.MaxAge > 0
+}
+
+// expired は新しい順の versions のうち policy で削除する版を返す
+func (p RetentionPolicy) expired(versions []Version, now time.Time) []Version {
+	if !p.enabled() {
+		return nil
+	}
+
+	var expired []Version
+	for i, v := range versions {
+		if i == 0 {
+			continue
+		}
+
+		if p.KeepLast > 0 && i < p.KeepLast {
+			continue
+		}
+
+		if p.MaxAge > 0 && now.Sub(v.Time) <= p.MaxAge {
+			continue
+		}
+
+		expired = append(expired, v)
+	}
+
+	return expired
+}
+
+func sortVersions(versions []Version) {
+	slices.SortStableFunc(versions, func(a, b Version) int {
+		return b.Time.Compare(a.Time)
+	})
+}
@@ -16,9 +16,13 @@ const (
 )
 
 type Client struct {
-	httpClient *http.Client
-	baseURL    *url.URL
-	headers    http.Header
+	httpClient         *http.Client
+	baseURL            *url.URL
+	headers            http.Header
+	retryPolicy        RetryPolicy
+	retryNonIdempotent bool
+	rateLimiter        *RateLimiter
+	sharedRateLimit    *sharedRateLimit
 }
 
 type Option func(*Client)
@@ -41,6 +45,32 @@ func WithHTTPClient(hc *http.Client) Option {
 	}
 }
 
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithRetryNonIdempotent は POST のような冪等でないリクエストも再試行の対象にする
+func WithRetryNonIdempotent() Option {
+	return func(c *Client) {
+		c.retryNonIdempotent = true
+	}
+}
+
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.rateLimiter = limiter
+	}
+}
+
+// WithSharedRateLimiter はベースURLのホストとヘッダー keyHeader の値の組毎に共有されるレートリミッターを使う
+func WithSharedRateLimiter(keyHeader string, rate float64, burst int) Option {
+	return func(c *Client) {
+		c.sharedRateLimit = &sharedRateLimit{keyHeader: keyHeader, rate: rate, burst: burst}
+	}
+}
+
 func NewClient(baseURLStr string, opts ...Option) (*Client, error) {
 	baseURL, err := url.Parse(baseURLStr)
 	if err != nil {
@@ -71,12 +101,29 @@ func (c *Client) clone() *Client {
 	}
 
 	return &Client{
-		httpClient: newHTTPClient,
-		baseURL:    c.baseURL,
-		headers:    c.headers.Clone(),
+		httpClient:         newHTTPClient,
+		baseURL:            c.baseURL,
+		headers:            c.headers.Clone(),
+		retryPolicy:        c.retryPolicy,
+		retryNonIdempotent: c.retryNonIdempotent,
+		rateLimiter:        c.rateLimiter,
+		sharedRateLimit:    c.sharedRateLimit,
 	}
 }
 
+func (c *Client) RateLimiter() *RateLimiter {
+	if c.rateLimiter != nil {
+		return c.rateLimiter
+	}
+
+	if c.sharedRateLimit != nil {
+		key := c.baseURL.Host + "|" + c.headers.Get(c.sharedRateLimit.keyHeader)
+		return SharedRateLimiter(key, c.sharedRateLimit.rate, c.sharedRateLimit.burst)
+	}
+
+	return nil
+}
+
 func (c *Client) do(ctx context.Context, method, path string, requestBody, responseBody any) error {
 	rel, err := url.Parse(path)
 	if err != nil {
@@ -84,44 +131,81 @@ func (c *Client) do(ctx context.Context, method, path string, requestBody, respo
 	}
 	fullURL := c.baseURL.ResolveReference(rel)
 
-	var bodyReader io.Reader
+	var bodyBytes []byte
 	if requestBody != nil {
-		bodyBytes, err := json.Marshal(requestBody)
+		bodyBytes, err = json.Marshal(requestBody)
 		if err != nil {
 			return fmt.Errorf("failed to marshal request body: %w", err)
 		}
+	}
+
+	retryable := c.retryPolicy != nil && (isIdempotent(method) || c.retryNonIdempotent)
+	limiter := c.RateLimiter()
+
+	for attempt := 1; ; attempt++ {
+		if limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+
+		resp, err := c.send(ctx, method, fullURL.String(), bodyBytes)
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			defer resp.Body.Close()
+
+			if responseBody != nil {
+				if err := json.NewDecoder(resp.Body).Decode(responseBody); err != nil {
+					return fmt.Errorf("failed to decode response body: %w", err)
+				}
+			}
+
+			return nil
+		}
+
+		if err == nil {
+			errBody, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			err = &HTTPError{
+				StatusCode: resp.StatusCode,
+				Message:    fmt.Sprintf("request failed with status code %d", resp.StatusCode),
+				Body:       string(errBody),
+			}
+		}
+
+		if !retryable {
+			return err
+		}
+
+		delay, ok := c.retryPolicy.Backoff(attempt, resp, err)
+		if !ok {
+			return err
+		}
+
+		if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, url string, bodyBytes []byte) (*http.Response, error) {
+	var bodyReader io.Reader
+	if bodyBytes != nil {
 		bodyReader = bytes.NewReader(bodyBytes)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, fullURL.String(), bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header = c.headers.Clone()
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return &HTTPError{
-			StatusCode: resp.StatusCode,
-			Message:    fmt.Sprintf("request failed with status code %d", resp.StatusCode),
-			Body:       string(bodyBytes),
-		}
-	}
-
-	if responseBody != nil {
-		if err := json.NewDecoder(resp.Body).Decode(responseBody); err != nil {
-			return fmt.Errorf("failed to decode response body: %w", err)
-		}
+		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 
-	return nil
+	return resp, nil
 }
 
 func (c *Client) Do(ctx context.Context, method, path string, requestBody, responseBody any, opts ...Option) error {
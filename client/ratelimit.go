@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter はトークンバケット方式のレートリミッター
+// 複数の Client で共有できる
+type RateLimiter struct {
+	mu       sync.Mutex
+	rate     float64
+	burst    float64
+	tokens   float64
+	last     time.Time
+	requests int64
+	waited   int64
+	waitTime time.Duration
+}
+
+type RateLimiterStats struct {
+	// Requests: Wait を通過したリクエスト数
+	Requests int64
+	// Waited: トークン待ちが発生したリクエスト数
+	Waited int64
+	// WaitTime: トークン待ちに費やした合計時間
+	WaitTime time.Duration
+}
+
+// NewRateLimiter は 1 秒あたり rate 回、最大 burst 回まで連続してリクエストを許可するレートリミッターを返す
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	burst = max(burst, 1)
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait はトークンを取得できるまでブロックする
+// ctx のデッドラインまでにトークンを取得できない場合はすぐにエラーを返す
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l.rate <= 0 || math.IsInf(l.rate, 1) {
+		l.mu.Lock()
+		l.requests++
+		l.mu.Unlock()
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		l.requests++
+		l.mu.Unlock()
+		return nil
+	}
+
+	delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(delay)) {
+		l.mu.Unlock()
+		return fmt.Errorf("rate limiter: wait %s exceeds context deadline: %w", delay, context.DeadlineExceeded)
+	}
+	l.tokens--
+	l.mu.Unlock()
+
+	if err := sleepContext(ctx, delay); err != nil {
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+
+	l.mu.Lock()
+	l.requests++
+	l.waited++
+	l.waitTime += delay
+	l.mu.Unlock()
+
+	return nil
+}
+
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return RateLimiterStats{
+		Requests: l.requests,
+		Waited:   l.waited,
+		WaitTime: l.waitTime,
+	}
+}
+
+var (
+	sharedRateLimitersMu sync.Mutex
+	sharedRateLimiters   = map[string]*RateLimiter{}
+)
+
+// SharedRateLimiter は key 毎に共有されるレートリミッターを返す
+// 同じ key で既に作成されている場合は rate と burst は無視される
+func SharedRateLimiter(key string, rate float64, burst int) *RateLimiter {
+	sharedRateLimitersMu.Lock()
+	defer sharedRateLimitersMu.Unlock()
+
+	if l, ok := sharedRateLimiters[key]; ok {
+		return l
+	}
+
+	l := NewRateLimiter(rate, burst)
+	sharedRateLimiters[key] = l
+	return l
+}
+
+type sharedRateLimit struct {
+	keyHeader string
+	rate      float64
+	burst     int
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"maps"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	yno "github.com/murasame29/yno-sdk"
+)
+
+var deviceStatuses = []yno.DeviceStatus{
+	yno.DeviceStatusOnline,
+	yno.DeviceStatusOffline,
+	yno.DeviceStatusCommunicating,
+	yno.DeviceStatusProcessing,
+	yno.DeviceStatusError,
+}
+
+type collectorConfig struct {
+	CacheTTL    time.Duration
+	StatsWindow time.Duration
+	Period      time.Duration
+	Interfaces  []string
+	CpuIDs      []int
+	Concurrency int
+	Timeout     time.Duration
+}
+
+// collector は YNO から取得したメトリクスを CacheTTL の間キャッシュする
+// 一部のルーターの統計情報の取得に失敗した結果もキャッシュし、失敗は yno_scrape_error で示す
+// ルーターの一覧を取得できなかった結果はキャッシュしない
+type collector struct {
+	client *yno.YNOClient
+	config collectorConfig
+	logger *slog.Logger
+
+	// group は同時に届いたスクレイプの収集を1回にまとめる
+	group singleflight.Group
+
+	mu          sync.Mutex
+	cached      []byte
+	collectedAt time.Time
+	errorsTotal atomic.Int64
+}
+
+func newCollector(client *yno.YNOClient, config collectorConfig, logger *slog.Logger) *collector {
+	return &collector{
+		client: client,
+		config: config,
+		logger: logger,
+	}
+}
+
+// metrics はキャッシュが有効であればそれを返し、なければ YNO から収集する
+// 収集はスクレイプの ctx がキャンセルされても Timeout まで続け、次のスクレイプのためにキャッシュする
+// ctx がキャンセルされた場合は直前にキャッシュした結果を返し、まだ一度も収集できていなければ false を返す
+func (c *collector) metrics(ctx context.Context) ([]byte, bool) {
+	if cached, ok := c.fresh(); ok {
+		return cached, true
+	}
+
+	ch := c.group.DoChan("collect", func() (any, error) {
+		return c.refresh(), nil
+	})
+
+	select {
+	case result := <-ch:
+		b := result.Val.([]byte)
+		return b, b != nil
+	case <-ctx.Done():
+		cached, _ := c.fresh()
+		return cached, cached != nil
+	}
+}
+
+// ServeHTTP はメトリクスを Prometheus のテキスト形式で返す
+// 返せるメトリクスがない場合は 503 を返す
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b, ok := c.metrics(r.Context())
+	if !ok {
+		http.Error(w, "metrics are not collected yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(b)
+}
+
+// fresh はキャッシュした結果と、それが CacheTTL 以内のものかを返す
+func (c *collector) fresh() ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.cached, c.cached != nil && time.Since(c.collectedAt) < c.config.CacheTTL
+}
+
+// refresh は YNO から収集した結果を返す。ルーターの一覧を取得できた場合のみキャッシュする
+func (c *collector) refresh() []byte {
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	reg := newRegistry()
+	success := c.collect(ctx, reg)
+
+	reg.add("yno_exporter_collect_success", "Whether the last collection could list routers from YNO.", gauge, nil, boolToFloat(success))
+	reg.add("yno_exporter_collect_duration_seconds", "Duration of the last collection from YNO.", gauge, nil, time.Since(start).Seconds())
+	reg.add("yno_exporter_collect_timestamp_seconds", "Unix time of the last collection from YNO.", gauge, nil, float64(start.Unix()))
+	reg.add("yno_exporter_errors_total", "Number of failed YNO API calls.", counter, nil, float64(c.errorsTotal.Load()))
+
+	if limiter := c.client.RateLimiter(); limiter != nil {
+		stats := limiter.Stats()
+		reg.add("yno_exporter_rate_limit_wait_seconds_total", "Time spent waiting for the client-side rate limiter.", counter, nil, stats.WaitTime.Seconds())
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var buf bytes.Buffer
+	if err := reg.writeTo(&buf); err != nil {
+		c.logger.Error("failed to render metrics", "error", err)
+		return c.cached
+	}
+
+	if !success {
+		return buf.Bytes()
+	}
+
+	c.cached = buf.Bytes()
+	c.collectedAt = start
+
+	return c.cached
+}
+
+// collect は reg にメトリクスを追加し、ルーターの一覧を取得できた場合 true を返す
+// オンラインのルーターごとに、統計情報の取得に1つでも失敗した場合は yno_scrape_error を 1 にする
+func (c *collector) collect(ctx context.Context, reg *registry) bool {
+	routers, err := yno.Collect(c.client.AllRouters(ctx, nil, yno.WithRetryNonIdempotent()), 0)
+	if err != nil {
+		c.errorsTotal.Add(1)
+		c.logger.Error("failed to search routers", "error", err)
+		return false
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(c.config.Concurrency, 1))
+
+	for _, router := range routers {
+		labels := routerLabels(router)
+
+		mu.Lock()
+		reg.add("yno_router_info", "Router metadata.", gauge, labels, 1)
+		for _, status := range deviceStatuses {
+			reg.add("yno_router_status", "Router DeviceStatus (1 for the current status).", gauge,
+				withLabel(labels, "status", string(status)), boolToFloat(router.DeviceStatus == string(status)))
+		}
+		mu.Unlock()
+
+		if router.DeviceStatus != string(yno.DeviceStatusOnline) {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			samples, ok := c.collectStats(ctx, router.SerialNumber, labels)
+
+			mu.Lock()
+			defer mu.Unlock()
+			reg.add("yno_scrape_error", "Whether collecting device statistics for the router failed (1) or not (0).", gauge, labels, boolToFloat(!ok))
+			for _, s := range samples {
+				reg.add(s.name, s.help, gauge, s.labels, s.value)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return true
+}
+
+type statSample struct {
+	name   string
+	help   string
+	labels map[string]string
+	value  float64
+}
+
+type statQuery struct {
+	name   string
+	help   string
+	typ    yno.DeviceStatType
+	params yno.Parameter
+	labels map[string]string
+}
+
+func (c *collector) statQueries(labels map[string]string) []statQuery {
+	queries := []statQuery{
+		{"yno_router_memory_utilization_percent", "Memory utilization.", yno.DeviceStatTypeMemoryUtilization, nil, labels},
+		{"yno_router_nat_sessions", "Number of NAT sessions.", yno.DeviceStatTypeNumberOfNatSessions, nil, labels},
+		{"yno_router_dynamic_filter_sessions", "Number of dynamic filter sessions.", yno.DeviceStatTypeNumberOfDynamicFilterSessions, nil, labels},
+	}
+
+	for _, cpuID := range c.config.CpuIDs {
+		queries = append(queries, statQuery{
+			"yno_router_cpu_utilization_percent", "CPU utilization.", yno.DeviceStatTypeCpuUtilization,
+			yno.CpuUtilizationParameter{CpuId: yno.Ptr(cpuID)},
+			withLabel(labels, "cpu", strconv.Itoa(cpuID)),
+		})
+	}
+
+	for _, iface := range c.config.Interfaces {
+		for _, direction := range []yno.TrafficDirection{yno.TrafficDirectionIn, yno.TrafficDirectionOut} {
+			queries = append(queries, statQuery{
+				"yno_router_traffic", "Amount of traffic.", yno.DeviceStatTypeAmountOfTraffic,
+				yno.AmountOfTrafficParameter{Direction: yno.Ptr(direction), Interface: yno.Ptr(iface)},
+				withLabel(withLabel(labels, "interface", iface), "direction", strings.ToLower(string(direction))),
+			})
+		}
+	}
+
+	return queries
+}
+
+// collectStats はルーター1台の統計情報を取得し、全ての取得に成功した場合 true を返す
+func (c *collector) collectStats(ctx context.Context, serialNumber string, labels map[string]string) ([]statSample, bool) {
+	to := time.Now()
+	from := to.Add(-c.config.StatsWindow)
+
+	ok := true
+	var samples []statSample
+	for _, q := range c.statQueries(labels) {
+		series, err := c.client.DeviceStats(ctx, serialNumber, q.typ, from, to, c.config.Period, yno.StatisticTypeAverage, q.params, yno.WithRetryNonIdempotent())
+		if err != nil {
+			c.errorsTotal.Add(1)
+			c.logger.Warn("failed to get device statistics", "serial", serialNumber, "type", q.typ, "error", err)
+			ok = false
+			continue
+		}
+
+		if len(series) == 0 {
+			continue
+		}
+
+		samples = append(samples, statSample{
+			name:   q.name,
+			help:   q.help,
+			labels: q.labels,
+			value:  float64(series[len(series)-1].Value),
+		})
+	}
+
+	return samples, ok
+}
+
+func routerLabels(router yno.RouterResponseRouter) map[string]string {
+	return map[string]string{
+		"serial_number":     router.SerialNumber,
+		"model_name":        router.ModelName,
+		"firmware_revision": router.FirmwareRevision,
+		"assigned_labels":   strings.Join(router.AssignedLabels, ","),
+	}
+}
+
+func withLabel(labels map[string]string, key, value string) map[string]string {
+	l := maps.Clone(labels)
+	l[key] = value
+	return l
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	yno "github.com/murasame29/yno-sdk"
+	"github.com/murasame29/yno-sdk/ynotest"
+)
+
+func newTestCollector(t *testing.T, s *ynotest.Server) *collector {
+	t.Helper()
+
+	c, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return newCollector(c, collectorConfig{
+		CacheTTL:    time.Hour,
+		StatsWindow: 30 * time.Minute,
+		Period:      5 * time.Minute,
+		Concurrency: 1,
+		Timeout:     10 * time.Second,
+	}, slog.New(slog.DiscardHandler))
+}
+
+// sampleValue は Prometheus のテキスト形式の出力から name のメトリクスのうち serial_number が一致する値を返す
+func sampleValue(output, name, serialNumber string) (string, bool) {
+	for line := range strings.Lines(output) {
+		if !strings.HasPrefix(line, name+"{") && !strings.HasPrefix(line, name+" ") {
+			continue
+		}
+
+		if serialNumber != "" && !strings.Contains(line, `serial_number="`+serialNumber+`"`) {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		return fields[len(fields)-1], true
+	}
+
+	return "", false
+}
+
+func TestCollectorPartialFailure(t *testing.T) {
+	s := ynotest.NewServer(ynotest.WithRouters(
+		yno.RouterResponseRouter{SerialNumber: "S1", DeviceStatus: string(yno.DeviceStatusOnline)},
+		yno.RouterResponseRouter{SerialNumber: "S2", DeviceStatus: string(yno.DeviceStatusOnline)},
+		yno.RouterResponseRouter{SerialNumber: "S3", DeviceStatus: string(yno.DeviceStatusOffline)},
+	))
+	defer s.Close()
+
+	ts := int(time.Now().Add(-time.Minute).Unix())
+	s.AddDeviceStatistics("S1", yno.DeviceStatTypeNumberOfNatSessions, nil, yno.DeviceStatistic{Timestamp: ts, Value: 10})
+	s.AddDeviceStatistics("S2", yno.DeviceStatTypeNumberOfNatSessions, nil, yno.DeviceStatistic{Timestamp: ts, Value: 20})
+
+	// 最初の統計情報の取得 (S1 のメモリ使用率) のみ失敗させる
+	s.InjectFault(ynotest.Fault{Method: http.MethodPost, PathPrefix: "/devicestats/", StatusCode: http.StatusInternalServerError, Count: 1})
+
+	c := newTestCollector(t, s)
+
+	b, ok := c.metrics(context.Background())
+	if !ok {
+		t.Fatal("metrics() returned no metrics")
+	}
+	output := string(b)
+
+	tests := []struct {
+		name, serialNumber, want string
+	}{
+		{"yno_exporter_collect_success", "", "1"},
+		{"yno_scrape_error", "S1", "1"},
+		{"yno_scrape_error", "S2", "0"},
+		{"yno_router_nat_sessions", "S1", "10"},
+		{"yno_router_nat_sessions", "S2", "20"},
+	}
+	for _, tt := range tests {
+		if got, _ := sampleValue(output, tt.name, tt.serialNumber); got != tt.want {
+			t.Errorf("%s{serial_number=%q} = %q, want %q", tt.name, tt.serialNumber, got, tt.want)
+		}
+	}
+
+	if _, ok := sampleValue(output, "yno_scrape_error", "S3"); ok {
+		t.Error("yno_scrape_error is reported for an offline router")
+	}
+
+	// 一部が失敗した結果もキャッシュし、次のスクレイプでは YNO に問い合わせない
+	requests := len(s.Requests())
+	if _, ok := c.metrics(context.Background()); !ok {
+		t.Fatal("metrics() returned no metrics")
+	}
+	if got := len(s.Requests()); got != requests {
+		t.Errorf("cached scrape sent %d requests", got-requests)
+	}
+}
+
+func TestCollectorRouterSearchFailure(t *testing.T) {
+	s := ynotest.NewServer(ynotest.WithRouters(yno.RouterResponseRouter{SerialNumber: "S1", DeviceStatus: string(yno.DeviceStatusOffline)}))
+	defer s.Close()
+
+	s.InjectFault(ynotest.Fault{PathPrefix: "/routers/", StatusCode: http.StatusInternalServerError, Count: 1})
+
+	c := newTestCollector(t, s)
+
+	b, ok := c.metrics(context.Background())
+	if !ok {
+		t.Fatal("metrics() returned no metrics")
+	}
+	if got, _ := sampleValue(string(b), "yno_exporter_collect_success", ""); got != "0" {
+		t.Errorf("yno_exporter_collect_success = %q, want 0", got)
+	}
+
+	// ルーターの一覧を取得できなかった結果はキャッシュせず、次のスクレイプで再び収集する
+	b, _ = c.metrics(context.Background())
+	if got, _ := sampleValue(string(b), "yno_exporter_collect_success", ""); got != "1" {
+		t.Errorf("yno_exporter_collect_success after recovery = %q, want 1", got)
+	}
+}
+
+func TestCollectorServeHTTPBeforeFirstCollection(t *testing.T) {
+	s := ynotest.NewServer()
+	defer s.Close()
+
+	s.InjectFault(ynotest.Fault{PathPrefix: "/routers/", Latency: 300 * time.Millisecond})
+
+	c := newTestCollector(t, s)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil).WithContext(ctx))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestCollectorServeHTTP(t *testing.T) {
+	s := ynotest.NewServer()
+	defer s.Close()
+
+	rec := httptest.NewRecorder()
+	newTestCollector(t, s).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if !strings.Contains(rec.Body.String(), "yno_exporter_collect_success 1\n") {
+		t.Errorf("body does not report a successful collection:\n%s", rec.Body)
+	}
+}
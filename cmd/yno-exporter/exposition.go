@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type metricType string
+
+const (
+	gauge   metricType = "gauge"
+	counter metricType = "counter"
+)
+
+type sample struct {
+	labels map[string]string
+	value  float64
+}
+
+type metricFamily struct {
+	name    string
+	help    string
+	typ     metricType
+	samples []sample
+}
+
+// registry は Prometheus のテキスト形式で出力するメトリクスの集合
+type registry struct {
+	families map[string]*metricFamily
+	order    []string
+}
+
+func newRegistry() *registry {
+	return &registry{families: map[string]*metricFamily{}}
+}
+
+func (r *registry) add(name, help string, typ metricType, labels map[string]string, value float64) {
+	family, ok := r.families[name]
+	if !ok {
+		family = &metricFamily{name: name, help: help, typ: typ}
+		r.families[name] = family
+		r.order = append(r.order, name)
+	}
+
+	family.samples = append(family.samples, sample{labels: labels, value: value})
+}
+
+func (r *registry) writeTo(w io.Writer) error {
+	for _, name := range r.order {
+		family := r.families[name]
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", family.name, escapeHelp(family.help), family.name, family.typ); err != nil {
+			return err
+		}
+
+		for _, s := range family.samples {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", family.name, formatLabels(s.labels), strconv.FormatFloat(s.value, 'g', -1, 64)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", k, escapeLabelValue(labels[k]))
+	}
+	b.WriteByte('}')
+
+	return b.String()
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeLabelValue(v string) string {
+	return labelValueReplacer.Replace(v)
+}
+
+var helpReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeHelp(v string) string {
+	return helpReplacer.Replace(v)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistryWriteTo(t *testing.T) {
+	reg := newRegistry()
+	reg.add("yno_router_info", "Router metadata.", gauge, map[string]string{"serial_number": "S1", "model_name": `RTX "1300"`}, 1)
+	reg.add("yno_exporter_errors_total", "Number of\nfailed calls.", counter, nil, 3)
+	reg.add("yno_router_info", "Router metadata.", gauge, map[string]string{"serial_number": "S2", "model_name": `a\b` + "\n"}, 1)
+	reg.add("yno_router_cpu_utilization_percent", "CPU utilization.", gauge, nil, 12.5)
+
+	var b strings.Builder
+	if err := reg.writeTo(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# HELP yno_router_info Router metadata.
+# TYPE yno_router_info gauge
+yno_router_info{model_name="RTX \"1300\"",serial_number="S1"} 1
+yno_router_info{model_name="a\\b\n",serial_number="S2"} 1
+# HELP yno_exporter_errors_total Number of\nfailed calls.
+# TYPE yno_exporter_errors_total counter
+yno_exporter_errors_total 3
+# HELP yno_router_cpu_utilization_percent CPU utilization.
+# TYPE yno_router_cpu_utilization_percent gauge
+yno_router_cpu_utilization_percent 12.5
+`
+	if got := b.String(); got != want {
+		t.Errorf("writeTo() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	yno "github.com/murasame29/yno-sdk"
+	"github.com/murasame29/yno-sdk/client"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var (
+		listen      = flag.String("listen", ":9876", "address to listen on")
+		baseURL     = flag.String("base-url", "", "YNO management API base URL (default $YNO_BASE_URL or the profile)")
+		apiKey      = flag.String("api-key", "", "YNO management API key (default $YNO_API_KEY or the profile)")
+		profile     = flag.String("profile", "", "profile in ~/.config/yno/config.yaml (default $YNO_PROFILE)")
+		cacheTTL    = flag.Duration("cache-ttl", 5*time.Minute, "how long to reuse collected metrics between scrapes")
+		statsWindow = flag.Duration("stats-window", 30*time.Minute, "time range used to look up the latest device statistics")
+		period      = flag.Duration("period", 5*time.Minute, "aggregation period of device statistics")
+		interfaces  = flag.String("interfaces", "LAN1,LAN2", "comma separated interfaces to collect traffic for")
+		cpuIDs      = flag.String("cpu-ids", "0", "comma separated CPU ids to collect utilization for")
+		concurrency = flag.Int("concurrency", 4, "number of routers to collect statistics for in parallel")
+		rate        = flag.Float64("rate", 5, "maximum YNO API requests per second")
+		timeout     = flag.Duration("timeout", 2*time.Minute, "timeout of a single collection")
+	)
+	flag.Parse()
+
+	cfg, err := yno.LoadConfig(context.Background(),
+		yno.WithConfigBaseURL(*baseURL),
+		yno.WithConfigAPIKey(*apiKey),
+		yno.WithProfile(*profile),
+	)
+	if err != nil {
+		return err
+	}
+
+	ids, err := parseInts(*cpuIDs)
+	if err != nil {
+		return fmt.Errorf("invalid -cpu-ids: %w", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	ynoClient, err := yno.NewClientFromConfig(cfg,
+		client.WithRetryPolicy(client.DefaultRetryPolicy()),
+		yno.WithRateLimit(*rate, int(max(*rate, 1))),
+	)
+	if err != nil {
+		return err
+	}
+
+	ynoClient.OnVersionMismatch = func(ctx context.Context, err *yno.VersionMismatchError) {
+		logger.WarnContext(ctx, "unsupported server MngApiVersion", "server", err.ServerVersion, "min", yno.MinSupportedMngAPIVersion, "max", yno.MaxSupportedMngAPIVersion)
+	}
+
+	c := newCollector(ynoClient, collectorConfig{
+		CacheTTL:    *cacheTTL,
+		StatsWindow: *statsWindow,
+		Period:      *period,
+		Interfaces:  splitList(*interfaces),
+		CpuIDs:      ids,
+		Concurrency: *concurrency,
+		Timeout:     *timeout,
+	}, logger)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", c)
+
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info("listening", "addr", *listen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseInts(s string) ([]int, error) {
+	var ints []int
+	for _, item := range splitList(s) {
+		i, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, err
+		}
+		ints = append(ints, i)
+	}
+	return ints, nil
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	yno "github.com/murasame29/yno-sdk"
+	"github.com/murasame29/yno-sdk/backup"
+	"github.com/murasame29/yno-sdk/rtxconfig"
+)
+
+func backupCommand() *command {
+	return &command{
+		name:    "backup",
+		summary: "back up router configs and export restore commands",
+		subcommands: []*command{
+			{name: "run", summary: "save the current config of routers", run: backupRun},
+			{name: "versions", summary: "list saved versions of a router", run: backupVersions},
+			{name: "diff", summary: "compare two saved versions of a router", run: backupDiff},
+			{name: "restore", summary: "print the commands to change the current config back to a saved version", run: backupRestore},
+		},
+	}
+}
+
+// storeFlags はバックアップの保存先を指定するフラグ
+type storeFlags struct {
+	dir string
+	git bool
+}
+
+func addStoreFlags(fs *flag.FlagSet) *storeFlags {
+	f := &storeFlags{}
+	fs.StringVar(&f.dir, "dir", "", "directory to store the backups")
+	fs.BoolVar(&f.git, "git", false, "store the backups in a git repository in --dir")
+	return f
+}
+
+func (f *storeFlags) store() (backup.Store, error) {
+	if f.dir == "" {
+		return nil, errors.New("--dir is required")
+	}
+
+	if f.git {
+		return backup.NewGitStore(f.dir)
+	}
+	return backup.NewDirStore(f.dir)
+}
+
+func backupRun(ctx context.Context, stdout io.Writer, args []string) error {
+	fs, common := newFlagSet("backup run")
+	storeFlags := addStoreFlags(fs)
+	var serials stringList
+	fs.Var(&serials, "serial", "serial number of the target router (repeatable, comma separated; default: all routers)")
+	keep := fs.Int("keep", 0, "number of versions to keep per router (default: keep all)")
+	maxAge := fs.Duration("max-age", 0, "keep versions saved within this duration (default: keep all)")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	store, err := storeFlags.store()
+	if err != nil {
+		return err
+	}
+
+	c, err := common.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	runner, err := backup.NewRunner(c, store, backup.WithRetention(backup.RetentionPolicy{KeepLast: *keep, MaxAge: *maxAge}))
+	if errors.Is(err, backup.ErrRetentionNotSupported) {
+		return errors.New("--keep and --max-age cannot be used with --git: the git history keeps every version")
+	}
+	if err != nil {
+		return err
+	}
+
+	serialNumbers := serials.split()
+	if len(serialNumbers) == 0 {
+		routers, err := yno.Collect(c.AllRouters(ctx, nil, yno.WithRetryNonIdempotent()), 0)
+		if err != nil {
+			return err
+		}
+		for _, router := range routers {
+			serialNumbers = append(serialNumbers, router.SerialNumber)
+		}
+	}
+
+	report, err := runner.Run(ctx, serialNumbers)
+	if report == nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(report.Results))
+	for _, result := range report.Results {
+		errMsg := ""
+		if result.Err != nil {
+			errMsg = result.Err.Error()
+		}
+		rows = append(rows, []string{result.SerialNumber, result.Version.ID, strconv.FormatBool(result.Changed), strconv.Itoa(len(result.Pruned)), errMsg})
+	}
+
+	if printErr := printOutput(stdout, common.output, report.Results, table{
+		headers: []string{"SERIAL NUMBER", "VERSION", "CHANGED", "PRUNED", "ERROR"},
+		rows:    rows,
+	}); printErr != nil {
+		return printErr
+	}
+
+	return err
+}
+
+func backupVersions(ctx context.Context, stdout io.Writer, args []string) error {
+	fs, common := newFlagSet("backup versions")
+	storeFlags := addStoreFlags(fs)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 {
+		return errors.New("usage: yno backup versions --dir <dir> <serial>")
+	}
+
+	store, err := storeFlags.store()
+	if err != nil {
+		return err
+	}
+
+	versions, err := store.Versions(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(versions))
+	for _, v := range versions {
+		rows = append(rows, []string{v.ID, v.Time.Local().Format("2006-01-02 15:04:05")})
+	}
+
+	return printOutput(stdout, common.output, versions, table{
+		headers: []string{"VERSION", "TIME"},
+		rows:    rows,
+	})
+}
+
+// backupDiff は2つの版の差分を区分ごとに表示する。--commands の場合は from を to にするコマンドを表示する
+func backupDiff(ctx context.Context, stdout io.Writer, args []string) error {
+	fs := flag.NewFlagSet("backup diff", flag.ContinueOnError)
+	storeFlags := addStoreFlags(fs)
+	commands := fs.Bool("commands", false, "print the commands to change the config from <from> to <to>")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) < 2 || len(positional) > 3 {
+		return errors.New("usage: yno backup diff --dir <dir> <serial> <from> [to]")
+	}
+
+	store, err := storeFlags.store()
+	if err != nil {
+		return err
+	}
+
+	// to を省略した場合は最新の版と比較する
+	ids := []string{positional[1], ""}
+	if len(positional) == 3 {
+		ids[1] = positional[2]
+	}
+
+	configs := make([]*rtxconfig.Config, len(ids))
+	for i, id := range ids {
+		text, err := store.Load(ctx, positional[0], id)
+		if err != nil {
+			return err
+		}
+
+		if configs[i], err = rtxconfig.Parse(text); err != nil {
+			return err
+		}
+	}
+
+	diff := rtxconfig.Compare(configs[0], configs[1])
+	if *commands {
+		for _, command := range diff.Commands() {
+			fmt.Fprintln(stdout, command)
+		}
+		return nil
+	}
+
+	fmt.Fprint(stdout, diff)
+	return nil
+}
+
+// backupRestore は現在の設定を保存した版に戻すコマンドを表示する
+func backupRestore(ctx context.Context, stdout io.Writer, args []string) error {
+	fs, common := newFlagSet("backup restore")
+	storeFlags := addStoreFlags(fs)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) < 1 || len(positional) > 2 {
+		return errors.New("usage: yno backup restore --dir <dir> <serial> [version]")
+	}
+
+	store, err := storeFlags.store()
+	if err != nil {
+		return err
+	}
+
+	var id string
+	if len(positional) == 2 {
+		id = positional[1]
+	}
+
+	c, err := common.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	runner, err := backup.NewRunner(c, store)
+	if err != nil {
+		return err
+	}
+
+	commands, err := runner.ExportRestore(ctx, positional[0], id)
+	if err != nil {
+		return err
+	}
+
+	for _, command := range commands {
+		fmt.Fprintln(stdout, command)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	yno "github.com/murasame29/yno-sdk"
+	"github.com/murasame29/yno-sdk/client"
+)
+
+func newYNOClient(ctx context.Context, configPath, profile string) (*yno.YNOClient, error) {
+	cfg, err := yno.LoadConfig(ctx, yno.WithConfigFile(configPath), yno.WithProfile(profile))
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := yno.NewClientFromConfig(cfg, client.WithRetryPolicy(client.DefaultRetryPolicy()))
+	if err != nil {
+		return nil, err
+	}
+
+	c.OnWarning = printWarnings
+	c.OnVersionMismatch = printVersionMismatch
+	return c, nil
+}
+
+func printVersionMismatch(_ context.Context, err *yno.VersionMismatchError) {
+	fmt.Fprintf(os.Stderr, "warning: %s\n", err)
+}
+
+func printWarnings(_ context.Context, op string, warnings []yno.Warning) {
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s: %s\n", op, w)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	yno "github.com/murasame29/yno-sdk"
+)
+
+type command struct {
+	name        string
+	summary     string
+	subcommands []*command
+	run         func(ctx context.Context, stdout io.Writer, args []string) error
+}
+
+func (c *command) execute(ctx context.Context, stdout io.Writer, path string, args []string) error {
+	if c.run != nil {
+		return c.run(ctx, stdout, args)
+	}
+
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		c.usage(os.Stderr, path)
+		if len(args) == 0 {
+			return errUsage
+		}
+		return nil
+	}
+
+	for _, sub := range c.subcommands {
+		if sub.name == args[0] {
+			return sub.execute(ctx, stdout, path+" "+sub.name, args[1:])
+		}
+	}
+
+	c.usage(os.Stderr, path)
+	return fmt.Errorf("unknown command %q", path+" "+args[0])
+}
+
+func (c *command) usage(w io.Writer, path string) {
+	fmt.Fprintf(w, "Usage: %s <command> [flags]\n\nCommands:\n", path)
+	for _, sub := range c.subcommands {
+		fmt.Fprintf(w, "  %-10s %s\n", sub.name, sub.summary)
+	}
+}
+
+var errUsage = errors.New("usage error")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	root := &command{
+		name: "yno",
+		subcommands: []*command{
+			routersCommand(),
+			usersCommand(),
+			tasksCommand(),
+			statsCommand(),
+			backupCommand(),
+		},
+	}
+
+	if err := root.execute(ctx, os.Stdout, "yno", os.Args[1:]); err != nil {
+		if !errors.Is(err, errUsage) && !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// commonFlags は全てのサブコマンドで使えるフラグ
+type commonFlags struct {
+	configPath string
+	profile    string
+	output     outputFormat
+}
+
+func newFlagSet(name string) (*flag.FlagSet, *commonFlags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	common := &commonFlags{output: outputTable}
+	fs.StringVar(&common.configPath, "config", "", "path to the config file (default $YNO_CONFIG_FILE or ~/.config/yno/config.yaml)")
+	fs.StringVar(&common.profile, "profile", "", "profile in the config file (default $YNO_PROFILE)")
+	fs.Var(&common.output, "o", "output format: table, json or yaml")
+	return fs, common
+}
+
+func (f *commonFlags) client(ctx context.Context) (*yno.YNOClient, error) {
+	return newYNOClient(ctx, f.configPath, f.profile)
+}
+
+// stringList は複数回指定できるフラグ。カンマ区切りでも指定できる
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+func (l stringList) split() []string {
+	var items []string
+	for _, v := range l {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// parseArgs はフラグと位置引数が混在した args を解析し、位置引数を返す
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+type outputFormat string
+
+const (
+	outputTable outputFormat = "table"
+	outputJSON  outputFormat = "json"
+	outputYAML  outputFormat = "yaml"
+)
+
+func (f *outputFormat) String() string {
+	return string(*f)
+}
+
+func (f *outputFormat) Set(s string) error {
+	switch outputFormat(s) {
+	case outputTable, outputJSON, outputYAML:
+		*f = outputFormat(s)
+		return nil
+	}
+
+	return fmt.Errorf("unknown output format %q (table, json, yaml)", s)
+}
+
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+// printOutput は format に応じて v を JSON か YAML で、または t を表形式で出力する
+func printOutput(w io.Writer, format outputFormat, v any, t table) error {
+	switch format {
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case outputYAML:
+		// json タグのフィールド名で出力するために一度 JSON を経由する
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		var generic any
+		if err := json.Unmarshal(b, &generic); err != nil {
+			return err
+		}
+
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(generic); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(t.headers, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	yno "github.com/murasame29/yno-sdk"
+)
+
+func routersCommand() *command {
+	return &command{
+		name:    "routers",
+		summary: "list routers and manage their labels",
+		subcommands: []*command{
+			{name: "list", summary: "list routers matching a query", run: routersList},
+			{
+				name:    "label",
+				summary: "add or remove router labels",
+				subcommands: []*command{
+					{name: "list", summary: "list all labels in the tenant", run: routersLabelList},
+					{name: "add", summary: "add labels to a router", run: routersLabelAdd},
+					{name: "remove", summary: "remove labels from a router", run: routersLabelRemove},
+					{name: "apply", summary: "add labels to every router matching a query", run: routersLabelApply},
+				},
+			},
+		},
+	}
+}
+
+func routersList(ctx context.Context, stdout io.Writer, args []string) error {
+	fs, common := newFlagSet("routers list")
+	where := fs.String("where", "", `filter such as 'model = RTX1300 and status in [Offline, Error]'`)
+	limit := fs.Int("limit", 0, "maximum number of routers to list (0 for no limit)")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	query, err := yno.ParseRouterQuery(*where)
+	if err != nil {
+		return err
+	}
+
+	c, err := common.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	routers, err := yno.Collect(c.AllRouters(ctx, query, yno.WithRetryNonIdempotent()), *limit)
+	if err != nil {
+		return err
+	}
+
+	return printRouters(stdout, common.output, routers)
+}
+
+func printRouters(stdout io.Writer, format outputFormat, routers []yno.RouterResponseRouter) error {
+	t := table{headers: []string{"SERIAL", "MODEL", "FIRMWARE", "STATUS", "IP", "DESCRIPTION", "LABELS", "USERS"}}
+	for _, r := range routers {
+		t.rows = append(t.rows, []string{
+			r.SerialNumber, r.ModelName, r.FirmwareRevision, r.DeviceStatus, r.EndpointIPAddress, r.DeviceDescription,
+			strings.Join(r.AssignedLabels, ","), strings.Join(r.AssignedUsers, ","),
+		})
+	}
+
+	if routers == nil {
+		routers = []yno.RouterResponseRouter{}
+	}
+
+	return printOutput(stdout, format, routers, t)
+}
+
+func routersLabelList(ctx context.Context, stdout io.Writer, args []string) error {
+	fs, common := newFlagSet("routers label list")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	c, err := common.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	labels, err := c.ListLabels(ctx, yno.WithRetryNonIdempotent())
+	if err != nil {
+		return err
+	}
+
+	if labels == nil {
+		labels = []string{}
+	}
+
+	t := table{headers: []string{"LABEL"}}
+	for _, label := range labels {
+		t.rows = append(t.rows, []string{label})
+	}
+
+	return printOutput(stdout, common.output, labels, t)
+}
+
+func routersLabelAdd(ctx context.Context, stdout io.Writer, args []string) error {
+	return routersLabelUpdate(ctx, stdout, "routers label add", args, (*yno.YNOClient).AddRouterLabels)
+}
+
+func routersLabelRemove(ctx context.Context, stdout io.Writer, args []string) error {
+	return routersLabelUpdate(ctx, stdout, "routers label remove", args, (*yno.YNOClient).RemoveRouterLabels)
+}
+
+type labelUpdateFunc func(c *yno.YNOClient, ctx context.Context, serialNumber string, labels []string, opts ...yno.OptionFunc) (*yno.RouterResponseRouter, error)
+
+func routersLabelUpdate(ctx context.Context, stdout io.Writer, name string, args []string, update labelUpdateFunc) error {
+	fs, common := newFlagSet(name)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) < 2 {
+		return fmt.Errorf("usage: yno %s <serial> <label>...", name)
+	}
+
+	c, err := common.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	router, err := update(c, ctx, positional[0], positional[1:], yno.WithRetryNonIdempotent())
+	if err != nil {
+		return err
+	}
+
+	return printRouters(stdout, common.output, []yno.RouterResponseRouter{*router})
+}
+
+func routersLabelApply(ctx context.Context, stdout io.Writer, args []string) error {
+	fs, common := newFlagSet("routers label apply")
+	where := fs.String("where", "", `routers to label such as 'model = RTX1300 and ip ~ 192.0.2.'`)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if *where == "" || len(positional) == 0 {
+		return errors.New("usage: yno routers label apply --where <query> <label>...")
+	}
+
+	query, err := yno.ParseRouterQuery(*where)
+	if err != nil {
+		return err
+	}
+
+	// 空白のみの条件は全ルーターを対象にしてしまうため受け付けない
+	if query.Where == nil {
+		return errors.New("usage: yno routers label apply --where <query> <label>...")
+	}
+
+	c, err := common.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, applyErr := c.ApplyLabels(ctx, *query.Where, positional, yno.WithRetryNonIdempotent())
+	if result == nil {
+		return applyErr
+	}
+
+	t := table{headers: []string{"SERIAL", "RESULT"}}
+	for _, r := range result.Updated {
+		t.rows = append(t.rows, []string{r.SerialNumber, "updated"})
+	}
+	for _, serialNumber := range result.Unchanged {
+		t.rows = append(t.rows, []string{serialNumber, "unchanged"})
+	}
+	for _, serialNumber := range slices.Sorted(maps.Keys(result.Failed)) {
+		t.rows = append(t.rows, []string{serialNumber, "failed: " + result.Failed[serialNumber].Error()})
+	}
+
+	if err := printOutput(stdout, common.output, labelApplyOutput(result), t); err != nil {
+		return err
+	}
+
+	return applyErr
+}
+
+// labelApplyOutput は json/yaml 出力用に LabelChangeResult のエラーを文字列にする
+func labelApplyOutput(result *yno.LabelChangeResult) map[string]any {
+	failed := map[string]string{}
+	for serialNumber, err := range result.Failed {
+		failed[serialNumber] = err.Error()
+	}
+
+	return map[string]any{
+		"Updated":   nonNil(result.Updated),
+		"Unchanged": nonNil(result.Unchanged),
+		"Failed":    failed,
+	}
+}
+
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
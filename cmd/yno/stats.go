@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	yno "github.com/murasame29/yno-sdk"
+)
+
+var statTypes = map[string]yno.DeviceStatType{
+	"memory":   yno.DeviceStatTypeMemoryUtilization,
+	"cpu":      yno.DeviceStatTypeCpuUtilization,
+	"traffic":  yno.DeviceStatTypeAmountOfTraffic,
+	"fastpath": yno.DeviceStatTypeNumberOfFastPathFlows,
+	"nat":      yno.DeviceStatTypeNumberOfNatSessions,
+	"filter":   yno.DeviceStatTypeNumberOfDynamicFilterSessions,
+}
+
+func statsCommand() *command {
+	cmd := &command{name: "stats", summary: "show device statistics"}
+	for _, name := range []string{"cpu", "memory", "traffic", "fastpath", "nat", "filter"} {
+		cmd.subcommands = append(cmd.subcommands, &command{
+			name:    name,
+			summary: string(statTypes[name]),
+			run: func(ctx context.Context, stdout io.Writer, args []string) error {
+				return statsShow(ctx, stdout, name, args)
+			},
+		})
+	}
+	return cmd
+}
+
+func statsShow(ctx context.Context, stdout io.Writer, name string, args []string) error {
+	fs, common := newFlagSet("stats " + name)
+	since := fs.Duration("since", time.Hour, "how far back to fetch statistics")
+	period := fs.Duration("period", 5*time.Minute, "aggregation period")
+	statistic := fs.String("stat", string(yno.StatisticTypeAverage), "statistic: Average or Maximum")
+	cpuID := fs.Int("cpu", 0, "CPU id (cpu)")
+	iface := fs.String("interface", "LAN1", "interface name (traffic)")
+	direction := fs.String("direction", string(yno.TrafficDirectionIn), "traffic direction: In or Out (traffic)")
+	ipVersion := fs.String("ip-version", string(yno.IPv4), "IP version: 4 or 6 (fastpath)")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 {
+		return errors.New("usage: yno stats " + name + " <serial>")
+	}
+
+	statType := statTypes[name]
+
+	var params yno.Parameter
+	switch statType {
+	case yno.DeviceStatTypeCpuUtilization:
+		params = yno.CpuUtilizationParameter{CpuId: yno.Ptr(*cpuID)}
+	case yno.DeviceStatTypeAmountOfTraffic:
+		params = yno.AmountOfTrafficParameter{
+			Direction: yno.Ptr(yno.TrafficDirection(*direction)),
+			Interface: yno.Ptr(*iface),
+		}
+	case yno.DeviceStatTypeNumberOfFastPathFlows:
+		params = yno.NumberOfFastPathFlowsParameter{IpVersion: yno.Ptr(yno.IPVersion(*ipVersion))}
+	}
+
+	c, err := common.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	to := time.Now()
+	series, err := c.DeviceStats(ctx, positional[0], statType, to.Add(-*since), to, *period, yno.Statistic(*statistic), params, yno.WithRetryNonIdempotent())
+	if err != nil {
+		return err
+	}
+
+	if series == nil {
+		series = []yno.DeviceStatPoint{}
+	}
+
+	t := table{headers: []string{"TIME", "VALUE"}}
+	for _, point := range series {
+		t.rows = append(t.rows, []string{point.Time.Local().Format(time.RFC3339), strconv.Itoa(point.Value)})
+	}
+
+	return printOutput(stdout, common.output, series, t)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	yno "github.com/murasame29/yno-sdk"
+)
+
+func tasksCommand() *command {
+	return &command{
+		name:    "tasks",
+		summary: "run commands on routers and inspect task results",
+		subcommands: []*command{
+			{name: "run", summary: "run commands on routers", run: tasksRun},
+			{name: "get", summary: "show the results of a task", run: tasksGet},
+		},
+	}
+}
+
+func tasksRun(ctx context.Context, stdout io.Writer, args []string) error {
+	fs, common := newFlagSet("tasks run")
+	var serials, commands stringList
+	fs.Var(&serials, "serial", "serial number of the target router (repeatable, comma separated)")
+	fs.Var(&commands, "cmd", "command to run (repeatable)")
+	timeout := fs.Duration("timeout", 0, "task timeout between 60s and 1800s (default: server default)")
+	noWait := fs.Bool("no-wait", false, "print the task id without waiting for the results")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	if len(serials.split()) == 0 || len(commands) == 0 {
+		return errors.New("usage: yno tasks run --serial <serial>... --cmd <command>...")
+	}
+
+	var taskTimeout *int
+	if *timeout > 0 {
+		taskTimeout = yno.Ptr(int(timeout.Seconds()))
+	}
+
+	c, err := common.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *noWait {
+		resp, err := c.CreateTask(ctx, &yno.CreateTaskRequest{
+			Type:    yno.Ptr(yno.TaskTypeExecuteCommand),
+			Timeout: taskTimeout,
+			Parameters: &yno.TaskParameter{
+				SerialNumbers: serials.split(),
+				Commands:      commands,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		if resp.Data == nil {
+			return errors.New("task id was not returned")
+		}
+
+		return printOutput(stdout, common.output, resp.Data, table{
+			headers: []string{"TASK ID"},
+			rows:    [][]string{{resp.Data.TaskId}},
+		})
+	}
+
+	// 1000 台または 100 コマンドを超える場合は複数のタスクに分割して実行する
+	fleet, err := c.RunCommandsOnFleet(ctx, serials.split(), commands, &yno.RunCommandsOnFleetOptions{Timeout: taskTimeout})
+	if fleet == nil {
+		return err
+	}
+
+	result := &yno.TaskResult{
+		TaskID:   strings.Join(fleet.TaskIDs(), ","),
+		Type:     yno.TaskTypeExecuteCommand,
+		Warnings: fleet.Warnings,
+	}
+	for _, serialNumber := range slices.Sorted(maps.Keys(fleet.Devices)) {
+		result.Devices = append(result.Devices, fleet.Devices[serialNumber])
+	}
+
+	if printErr := printTaskResult(stdout, common.output, result); printErr != nil {
+		return printErr
+	}
+
+	return err
+}
+
+func tasksGet(ctx context.Context, stdout io.Writer, args []string) error {
+	fs, common := newFlagSet("tasks get")
+	wait := fs.Bool("wait", false, "wait until every device has finished")
+	timeout := fs.Duration("timeout", 30*time.Minute, "how long to wait with --wait")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 {
+		return errors.New("usage: yno tasks get <task-id>")
+	}
+	taskID := positional[0]
+
+	c, err := common.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *wait {
+		result, err := c.WaitForTask(ctx, taskID, &yno.WaitForTaskOptions{Timeout: *timeout})
+		if err != nil && !errors.Is(err, yno.ErrTaskWaitTimeout) {
+			return err
+		}
+
+		if printErr := printTaskResult(stdout, common.output, result); printErr != nil {
+			return printErr
+		}
+		return err
+	}
+
+	devices, err := yno.Collect(c.AllTaskDevices(ctx, taskID), 0)
+	if err != nil {
+		return err
+	}
+
+	return printTaskResult(stdout, common.output, &yno.TaskResult{TaskID: taskID, Devices: devices})
+}
+
+// printTaskResult は表形式の場合はデバイス毎にコマンドと出力を並べて表示する
+func printTaskResult(stdout io.Writer, format outputFormat, result *yno.TaskResult) error {
+	if format != outputTable {
+		return printOutput(stdout, format, result, table{})
+	}
+
+	var b strings.Builder
+	for _, device := range result.Devices {
+		fmt.Fprintf(&b, "=== %s (%s)\n", device.SerialNumber, device.Status)
+		for _, cr := range device.CommandResults {
+			fmt.Fprintf(&b, "$ %s [%s]\n", cr.Command, cr.ExitCode)
+			for _, line := range cr.Output {
+				fmt.Fprintln(&b, line)
+			}
+		}
+	}
+
+	_, err := io.WriteString(stdout, b.String())
+	return err
+}
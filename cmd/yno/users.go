@@ -0,0 +1,358 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	yno "github.com/murasame29/yno-sdk"
+)
+
+func usersCommand() *command {
+	return &command{
+		name:    "users",
+		summary: "manage users",
+		subcommands: []*command{
+			{name: "list", summary: "list users matching a query", run: usersList},
+			{name: "create", summary: "create a user", run: usersCreate},
+			{name: "update", summary: "update a user", run: usersUpdate},
+			{name: "delete", summary: "delete a user", run: usersDelete},
+		},
+	}
+}
+
+func usersList(ctx context.Context, stdout io.Writer, args []string) error {
+	fs, common := newFlagSet("users list")
+	where := fs.String("where", "", `filter such as 'name ~ alice or email = alice@example.com'`)
+	limit := fs.Int("limit", 0, "maximum number of users to list (0 for no limit)")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	query, err := yno.ParseUserQuery(*where)
+	if err != nil {
+		return err
+	}
+
+	c, err := common.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	users, err := yno.Collect(c.AllUsers(ctx, query, yno.WithRetryNonIdempotent()), *limit)
+	if err != nil {
+		return err
+	}
+
+	if users == nil {
+		users = []yno.UserResponseUser{}
+	}
+
+	t := table{headers: []string{"NAME", "ENABLED", "EMAILS"}}
+	for _, u := range users {
+		t.rows = append(t.rows, []string{u.AccountName, strconv.FormatBool(u.AccountStatus), formatEmails(u.EmailAddressesForNotification)})
+	}
+
+	return printOutput(stdout, common.output, users, t)
+}
+
+// userFlags は create と update で共通のフラグ
+type userFlags struct {
+	passwordStdin bool
+	autoPassword  bool
+	emails        stringList
+}
+
+func addUserFlags(fs *flag.FlagSet, f *userFlags) {
+	fs.BoolVar(&f.passwordStdin, "password-stdin", false, "read the password from stdin")
+	fs.BoolVar(&f.autoPassword, "auto-password", false, "let YNO generate the password")
+	fs.Var(&f.emails, "email", "notification email as address[:JSON|Text] (repeatable)")
+}
+
+func (f *userFlags) password() (*string, error) {
+	if !f.passwordStdin {
+		return nil, nil
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return yno.Ptr(strings.TrimRight(line, "\r\n")), nil
+}
+
+func (f *userFlags) emailAddresses() ([]yno.EmailAddressesForNotification, error) {
+	var emails []yno.EmailAddressesForNotification
+	for _, v := range f.emails.split() {
+		address, format := v, yno.FormatOfAlarmNotificationEmailBodyText
+		if i := strings.LastIndex(v, ":"); i >= 0 {
+			switch f := yno.FormatOfAlarmNotificationEmailBody(v[i+1:]); f {
+			case yno.FormatOfAlarmNotificationEmailBodyJson, yno.FormatOfAlarmNotificationEmailBodyText:
+				address, format = v[:i], f
+			default:
+				return nil, fmt.Errorf("unknown email body format %q (JSON, Text)", v[i+1:])
+			}
+		}
+
+		emails = append(emails, yno.EmailAddressesForNotification{
+			EmailAddress:                       yno.Ptr(address),
+			FormatOfAlarmNotificationEmailBody: yno.Ptr(format),
+		})
+	}
+
+	return emails, nil
+}
+
+func usersCreate(ctx context.Context, stdout io.Writer, args []string) error {
+	fs, common := newFlagSet("users create")
+	var uf userFlags
+	addUserFlags(fs, &uf)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 {
+		return errors.New("usage: yno users create <account-name> [--password-stdin | --auto-password] [--email address[:format]]...")
+	}
+
+	password, err := uf.password()
+	if err != nil {
+		return err
+	}
+
+	emails, err := uf.emailAddresses()
+	if err != nil {
+		return err
+	}
+
+	requestBody := &yno.CreateUserRequest{
+		AccountName:                   yno.Ptr(positional[0]),
+		Password:                      password,
+		EmailAddressesForNotification: emails,
+	}
+	if uf.autoPassword {
+		requestBody.AutoGeneratePassword = yno.Ptr(true)
+	}
+
+	c, err := common.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.CreateUser(ctx, requestBody)
+	if err != nil {
+		return err
+	}
+
+	return printUserData(stdout, common.output, resp.Data)
+}
+
+func usersUpdate(ctx context.Context, stdout io.Writer, args []string) error {
+	fs, common := newFlagSet("users update")
+	var uf userFlags
+	addUserFlags(fs, &uf)
+	enable := fs.Bool("enable", false, "enable the account")
+	disable := fs.Bool("disable", false, "disable the account")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 {
+		return errors.New("usage: yno users update <account-name> [--password-stdin | --auto-password] [--enable | --disable] [--email address[:format]]...")
+	}
+
+	if *enable && *disable {
+		return errors.New("--enable and --disable are mutually exclusive")
+	}
+
+	password, err := uf.password()
+	if err != nil {
+		return err
+	}
+
+	emails, err := uf.emailAddresses()
+	if err != nil {
+		return err
+	}
+
+	requestBody := &yno.UpdateUserRequest{
+		Password:                      password,
+		EmailAddressesForNotification: emails,
+	}
+	if uf.autoPassword {
+		requestBody.AutoGeneratePassword = yno.Ptr(true)
+	}
+	if *enable || *disable {
+		requestBody.AccountStatus = yno.Ptr(*enable)
+	}
+
+	c, err := common.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.UpdateUser(ctx, positional[0], requestBody)
+	if err != nil {
+		return err
+	}
+
+	return printUpdatedUserData(stdout, common.output, resp.Data)
+}
+
+func usersDelete(ctx context.Context, stdout io.Writer, args []string) error {
+	fs, common := newFlagSet("users delete")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 {
+		return errors.New("usage: yno users delete <account-name>")
+	}
+
+	c, err := common.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.DeleteUser(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	return printOutput(stdout, common.output, resp.Data, table{
+		headers: []string{"NAME", "RESULT"},
+		rows:    [][]string{{positional[0], resp.Data.Result}},
+	})
+}
+
+func usersRouters(ctx context.Context, stdout io.Writer, args []string) error {
+	fs, common := newFlagSet("users routers")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 {
+		return errors.New("usage: yno users routers <account-name>")
+	}
+
+	c, err := common.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	routers, err := yno.Collect(c.AllRouters(ctx, nil, yno.WithRetryNonIdempotent()), 0)
+	if err != nil {
+		return err
+	}
+
+	serialNumbers := yno.BuildUserRouterIndex(routers).Routers(positional[0])
+	assigned := []yno.RouterResponseRouter{}
+	for _, r := range routers {
+		if slices.Contains(serialNumbers, r.SerialNumber) {
+			assigned = append(assigned, r)
+		}
+	}
+
+	return printRouters(stdout, common.output, assigned)
+}
+
+func usersSync(ctx context.Context, stdout io.Writer, args []string) error {
+	fs, common := newFlagSet("users sync")
+	file := fs.String("f", "", "YAML or JSON file mapping account names to router serial numbers")
+	dryRun := fs.Bool("dry-run", false, "print the changes without updating routers")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	if *file == "" {
+		return errors.New("usage: yno users sync -f <file> [--dry-run]")
+	}
+
+	b, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	// YAML は JSON を含むため、どちらの形式も読める
+	var desired map[string][]string
+	if err := yaml.Unmarshal(b, &desired); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", *file, err)
+	}
+
+	c, err := common.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, syncErr := c.SyncUserAssignments(ctx, desired, &yno.SyncUserAssignmentsOptions{DryRun: *dryRun}, yno.WithRetryNonIdempotent())
+	if result == nil {
+		return syncErr
+	}
+
+	t := table{headers: []string{"SERIAL", "ADDED", "REMOVED", "RESULT"}}
+	for _, change := range result.Plan.Changes {
+		status := "updated"
+		switch err, failed := result.Failed[change.SerialNumber]; {
+		case failed:
+			status = "failed: " + err.Error()
+		case *dryRun:
+			status = "planned"
+		}
+
+		t.rows = append(t.rows, []string{change.SerialNumber, strings.Join(change.Added, ","), strings.Join(change.Removed, ","), status})
+	}
+
+	if err := printOutput(stdout, common.output, nonNil(result.Plan.Changes), t); err != nil {
+		return err
+	}
+
+	return syncErr
+}
+
+func printUserData(stdout io.Writer, format outputFormat, data yno.CreateuserResponseData) error {
+	return printUser(stdout, format, data, data.AccountName, data.AccountStatus, data.EmailAddressesForNotification, data.AutoGeneratedPassword)
+}
+
+func printUpdatedUserData(stdout io.Writer, format outputFormat, data yno.UpdateUserResponseData) error {
+	return printUser(stdout, format, data, data.AccountName, data.AccountStatus, data.EmailAddressesForNotification, data.AutoGeneratedPassword)
+}
+
+func printUser(stdout io.Writer, format outputFormat, data any, accountName string, accountStatus bool, emails []yno.EmailAddressesForNotification, generatedPassword string) error {
+	return printOutput(stdout, format, data, table{
+		headers: []string{"NAME", "ENABLED", "EMAILS", "GENERATED PASSWORD"},
+		rows: [][]string{{
+			accountName, strconv.FormatBool(accountStatus), formatEmails(emails), generatedPassword,
+		}},
+	})
+}
+
+func formatEmails(emails []yno.EmailAddressesForNotification) string {
+	var items []string
+	for _, e := range emails {
+		if e.EmailAddress == nil {
+			continue
+		}
+
+		item := *e.EmailAddress
+		if e.FormatOfAlarmNotificationEmailBody != nil {
+			item += ":" + string(*e.FormatOfAlarmNotificationEmailBody)
+		}
+		items = append(items, item)
+	}
+
+	return strings.Join(items, ",")
+}
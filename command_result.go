@@ -0,0 +1,44 @@
+package yno
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/murasame29/yno-sdk/parsers"
+)
+
+// ErrCommandNotFound は DeviceTaskResult に指定したコマンドの結果が含まれない場合に返される
+var ErrCommandNotFound = errors.New("yno: command result not found")
+
+// Command は command の実行結果を返す。コマンドは前後と連続する空白を無視して比較する
+func (r DeviceTaskResult) Command(command string) (CommandResultDetail, bool) {
+	command = strings.Join(strings.Fields(command), " ")
+	for _, cr := range r.CommandResults {
+		if strings.Join(strings.Fields(cr.Command), " ") == command {
+			return cr, true
+		}
+	}
+
+	return CommandResultDetail{}, false
+}
+
+// Parse は command の出力を parsers パッケージに登録されたパーサーで解析する
+func (r DeviceTaskResult) Parse(command string) (any, error) {
+	cr, ok := r.Command(command)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s: %q", ErrCommandNotFound, r.SerialNumber, command)
+	}
+
+	return cr.Parse()
+}
+
+// Parse は出力を parsers パッケージに登録されたパーサーで解析する
+// コマンドが成功していない場合はエラーを返す
+func (r CommandResultDetail) Parse() (any, error) {
+	if r.ExitCode != ExitCodeSuccess {
+		return nil, fmt.Errorf("command %q exited with %s", r.Command, r.ExitCode)
+	}
+
+	return parsers.Parse(r.Command, r.Output)
+}
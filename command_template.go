@@ -0,0 +1,286 @@
+package yno
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// ErrLineBreakInValue はテンプレートに渡す値に改行が含まれる場合に返される
+var ErrLineBreakInValue = errors.New("yno: template value contains a line break")
+
+// CommandTemplate はルーターごとに描画するコマンドの text/template
+//
+//	tmpl, err := yno.ParseCommandTemplate(
+//		`description 1 "{{.DeviceDescription}}"`,
+//		`tunnel select {{.Vars.tunnel_id}}`,
+//		`{{range .Vars.networks}}ip route {{.}} gateway tunnel {{$.Vars.tunnel_id}}{{"\n"}}{{end}}`,
+//	)
+//
+// テンプレートには CommandTemplateData が渡される
+// 1つのテンプレートが複数行を出力した場合は行ごとに別のコマンドになり、空行は無視される
+// ルーターの情報や Vars の値に改行が含まれる場合は、意図しないコマンドにならないよう描画時に ErrLineBreakInValue を返す
+type CommandTemplate struct {
+	templates []*template.Template
+}
+
+// CommandTemplateData はテンプレートに渡される値
+// RouterResponseRouter のフィールドは {{.SerialNumber}} のように直接参照できる
+type CommandTemplateData struct {
+	RouterResponseRouter
+	// Vars: CommandVars.Global に CommandVars.Devices のシリアル番号ごとの値を上書きした値
+	Vars map[string]any
+}
+
+// CommandVars はテンプレートに渡す利用者定義の変数
+type CommandVars struct {
+	// Global: 全てのルーターに渡す値
+	Global map[string]any
+	// Devices: シリアル番号ごとに Global を上書きする値
+	Devices map[string]map[string]any
+}
+
+// ParseCommandTemplate は commands をそれぞれテンプレートとして解析する
+// 存在しない Vars のキーを参照した場合は描画時にエラーになる
+func ParseCommandTemplate(commands ...string) (*CommandTemplate, error) {
+	if len(commands) == 0 {
+		return nil, ValidateErrorRequired{"Commands"}
+	}
+
+	t := &CommandTemplate{}
+	for i, command := range commands {
+		tmpl, err := template.New(fmt.Sprintf("command[%d]", i)).Option("missingkey=error").Parse(command)
+		if err != nil {
+			return nil, err
+		}
+		t.templates = append(t.templates, tmpl)
+	}
+
+	return t, nil
+}
+
+// Render は router に対するコマンドを描画する
+func (t *CommandTemplate) Render(router RouterResponseRouter, vars map[string]any) ([]string, error) {
+	data := CommandTemplateData{RouterResponseRouter: router, Vars: vars}
+	if data.Vars == nil {
+		data.Vars = map[string]any{}
+	}
+
+	if err := data.validate(); err != nil {
+		return nil, err
+	}
+
+	var commands []string
+	for _, tmpl := range t.templates {
+		var b bytes.Buffer
+		if err := tmpl.Execute(&b, data); err != nil {
+			return nil, err
+		}
+
+		for line := range strings.Lines(b.String()) {
+			if line = strings.TrimSpace(line); line != "" {
+				commands = append(commands, line)
+			}
+		}
+	}
+
+	return commands, nil
+}
+
+// validate はテンプレートに渡す値に改行が含まれないかを検査する
+// DeviceDescription などの値から改行を使って別のコマンドを差し込めないようにする
+// text/template はポインターを辿って描画するため、fmt で文字列にした値ではなく描画される値そのものを検査する
+func (d CommandTemplateData) validate() error {
+	return checkLineBreaks(reflect.ValueOf(d), "", map[lineBreakVisit]bool{})
+}
+
+type lineBreakVisit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+var (
+	stringerType = reflect.TypeFor[fmt.Stringer]()
+	errorType    = reflect.TypeFor[error]()
+)
+
+// checkLineBreaks は v に含まれる文字列を、ポインターとインターフェースを辿り、構造体、マップ、スライスの要素まで再帰的に検査する
+// String または Error メソッドを持つ値はテンプレートがその結果を出力するため、その結果も検査する
+func checkLineBreaks(v reflect.Value, path string, visited map[lineBreakVisit]bool) error {
+	if !v.IsValid() {
+		return nil
+	}
+
+	if v.CanInterface() && (v.Type().Implements(stringerType) || v.Type().Implements(errorType)) {
+		if !(v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) || !v.IsNil() {
+			if strings.ContainsAny(fmt.Sprint(v.Interface()), "\r\n") {
+				return fmt.Errorf("%w: %s", ErrLineBreakInValue, path)
+			}
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+
+		key := lineBreakVisit{ptr: v.Pointer(), typ: v.Type()}
+		if visited[key] {
+			return nil
+		}
+		visited[key] = true
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if strings.ContainsAny(v.String(), "\r\n") {
+			return fmt.Errorf("%w: %s", ErrLineBreakInValue, path)
+		}
+
+	case reflect.Pointer, reflect.Interface:
+		return checkLineBreaks(v.Elem(), path, visited)
+
+	case reflect.Struct:
+		for i := range v.NumField() {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			// 埋め込んだ構造体のフィールドはテンプレートから直接参照できるため、パスに型名を含めない
+			fieldPath := path
+			if !field.Anonymous {
+				fieldPath = joinPath(path, field.Name)
+			}
+
+			if err := checkLineBreaks(v.Field(i), fieldPath, visited); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+		})
+
+		for _, key := range keys {
+			keyPath := joinPath(path, fmt.Sprint(key))
+			if err := checkLineBreaks(key, keyPath, visited); err != nil {
+				return err
+			}
+			if err := checkLineBreaks(v.MapIndex(key), keyPath, visited); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			if err := checkLineBreaks(v.Index(i), fmt.Sprintf("%s[%d]", path, i), visited); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// CommandGroup は同じコマンドを実行するルーターのまとまり
+type CommandGroup struct {
+	Commands      []string
+	SerialNumbers []string
+}
+
+// RenderedCommands は RenderCommands の結果
+type RenderedCommands struct {
+	// Groups: 描画結果が同じルーターをまとめたもの。routers に最初に現れた順に並ぶ
+	Groups []CommandGroup
+	// Devices: シリアル番号ごとの描画したコマンド
+	Devices map[string][]string
+	// Skipped: コマンドが1つも描画されなかったルーターのシリアル番号
+	Skipped []string
+}
+
+// RenderCommands は routers ごとにコマンドを描画し、同じコマンドになったルーターをまとめる
+func RenderCommands(t *CommandTemplate, routers []RouterResponseRouter, vars *CommandVars) (*RenderedCommands, error) {
+	if vars == nil {
+		vars = &CommandVars{}
+	}
+
+	rendered := &RenderedCommands{Devices: map[string][]string{}}
+	groups := map[string]int{}
+	for _, router := range routers {
+		if _, ok := rendered.Devices[router.SerialNumber]; ok {
+			continue
+		}
+
+		deviceVars := maps.Clone(vars.Global)
+		if deviceVars == nil {
+			deviceVars = map[string]any{}
+		}
+		maps.Copy(deviceVars, vars.Devices[router.SerialNumber])
+
+		commands, err := t.Render(router, deviceVars)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", router.SerialNumber, err)
+		}
+
+		if len(commands) == 0 {
+			rendered.Skipped = append(rendered.Skipped, router.SerialNumber)
+			continue
+		}
+		rendered.Devices[router.SerialNumber] = commands
+
+		key := strings.Join(commands, "\n")
+		i, ok := groups[key]
+		if !ok {
+			i = len(rendered.Groups)
+			groups[key] = i
+			rendered.Groups = append(rendered.Groups, CommandGroup{Commands: commands})
+		}
+		rendered.Groups[i].SerialNumbers = append(rendered.Groups[i].SerialNumbers, router.SerialNumber)
+	}
+
+	return rendered, nil
+}
+
+// TemplatedCommandsResult は RunCommandTemplate の結果
+type TemplatedCommandsResult struct {
+	*FleetResult
+	Rendered *RenderedCommands
+}
+
+// RunCommandTemplate は routers ごとにコマンドを描画し、同じコマンドになったルーターをまとめて RunCommandsOnFleet と同様に実行する
+// Rendered でどのルーターにどのコマンドを送ったかを確認できる
+func (c *YNOClient) RunCommandTemplate(ctx context.Context, routers []RouterResponseRouter, t *CommandTemplate, vars *CommandVars, options *RunCommandsOnFleetOptions, opts ...OptionFunc) (*TemplatedCommandsResult, error) {
+	if options == nil {
+		options = &RunCommandsOnFleetOptions{}
+	}
+
+	rendered, err := RenderCommands(t, routers, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]fleetJob, 0, len(rendered.Groups))
+	for _, group := range rendered.Groups {
+		jobs = append(jobs, fleetJob{serialNumbers: group.SerialNumbers, commands: group.Commands})
+	}
+
+	result, err := c.runFleetJobs(ctx, jobs, options, opts)
+	return &TemplatedCommandsResult{FleetResult: result, Rendered: rendered}, err
+}
@@ -0,0 +1,163 @@
+package yno
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCommandTemplateRender(t *testing.T) {
+	tmpl, err := ParseCommandTemplate(
+		`description 1 "{{.DeviceDescription}}"`,
+		`{{range .Vars.networks}}ip route {{.}} gateway tunnel 1{{"\n"}}{{end}}`,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		router  RouterResponseRouter
+		vars    map[string]any
+		want    []string
+		wantErr error
+	}{
+		{
+			name:   "template line breaks split commands",
+			router: RouterResponseRouter{SerialNumber: "S1", DeviceDescription: "tokyo"},
+			vars:   map[string]any{"networks": []string{"10.0.0.0/8", "172.16.0.0/12"}},
+			want: []string{
+				`description 1 "tokyo"`,
+				"ip route 10.0.0.0/8 gateway tunnel 1",
+				"ip route 172.16.0.0/12 gateway tunnel 1",
+			},
+		},
+		{
+			name:    "line break in router data",
+			router:  RouterResponseRouter{SerialNumber: "S1", DeviceDescription: "tokyo\"\nno ip route default"},
+			vars:    map[string]any{"networks": []string{}},
+			wantErr: ErrLineBreakInValue,
+		},
+		{
+			name:    "line break in nested vars",
+			router:  RouterResponseRouter{SerialNumber: "S1"},
+			vars:    map[string]any{"networks": []string{"10.0.0.0/8\r\nclear configuration"}},
+			wantErr: ErrLineBreakInValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tmpl.Render(tt.router, tt.vars)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Render() error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+type commandTemplateRoute struct {
+	Network *string
+	Comment *string
+}
+
+type lineBreakStringer struct{}
+
+func (lineBreakStringer) String() string {
+	return "x\nclear configuration"
+}
+
+func TestCommandTemplateRenderPointerValues(t *testing.T) {
+	tmpl, err := ParseCommandTemplate(
+		`description 1 "{{.Vars.desc}}"`,
+		`{{range .Vars.routes}}ip route {{.Network}} gateway tunnel 1 # {{.Comment}}{{"\n"}}{{end}}`,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	route := func(network, comment string) *commandTemplateRoute {
+		return &commandTemplateRoute{Network: Ptr(network), Comment: Ptr(comment)}
+	}
+
+	tests := []struct {
+		name     string
+		vars     map[string]any
+		want     []string
+		wantPath string
+	}{
+		{
+			name: "pointer values are rendered",
+			vars: map[string]any{
+				"desc":   Ptr("tokyo"),
+				"routes": []*commandTemplateRoute{route("10.0.0.0/8", "hq")},
+			},
+			want: []string{`description 1 "tokyo"`, "ip route 10.0.0.0/8 gateway tunnel 1 # hq"},
+		},
+		{
+			name: "line break behind a pointer",
+			vars: map[string]any{
+				"desc":   Ptr("x\"\nclear configuration"),
+				"routes": []*commandTemplateRoute{},
+			},
+			wantPath: "Vars.desc",
+		},
+		{
+			name: "line break behind a pointer to a pointer",
+			vars: map[string]any{
+				"desc":   Ptr(Ptr("x\rclear configuration")),
+				"routes": []*commandTemplateRoute{},
+			},
+			wantPath: "Vars.desc",
+		},
+		{
+			name: "line break in a nested pointer field",
+			vars: map[string]any{
+				"desc":   "tokyo",
+				"routes": []*commandTemplateRoute{route("10.0.0.0/8", "hq"), route("172.16.0.0/12", "y\nno ip route default")},
+			},
+			wantPath: "Vars.routes[1].Comment",
+		},
+		{
+			name: "line break in a map key",
+			vars: map[string]any{
+				"desc":   map[string]string{"a\nclear configuration": "b"},
+				"routes": []*commandTemplateRoute{},
+			},
+			wantPath: "Vars.desc.a\nclear configuration",
+		},
+		{
+			name: "line break in String output",
+			vars: map[string]any{
+				"desc":   lineBreakStringer{},
+				"routes": []*commandTemplateRoute{},
+			},
+			wantPath: "Vars.desc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tmpl.Render(RouterResponseRouter{SerialNumber: "S1"}, tt.vars)
+			if tt.wantPath == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("Render() = %q, want %q", got, tt.want)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrLineBreakInValue) {
+				t.Fatalf("Render() = %q, %v, want %v", got, err, ErrLineBreakInValue)
+			}
+			if want := ErrLineBreakInValue.Error() + ": " + tt.wantPath; err.Error() != want {
+				t.Errorf("Render() error = %q, want %q", err, want)
+			}
+		})
+	}
+}
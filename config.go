@@ -0,0 +1,263 @@
+package yno
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/murasame29/yno-sdk/client"
+)
+
+const (
+	EnvAPIKey           = "YNO_API_KEY"
+	EnvBaseURL          = "YNO_BASE_URL"
+	EnvMngAPIVersion    = "YNO_MNGAPI_VERSION"
+	EnvProfile          = "YNO_PROFILE"
+	EnvConfigFile       = "YNO_CONFIG_FILE"
+	EnvCredentialHelper = "YNO_CREDENTIAL_HELPER"
+
+	defaultProfile           = "default"
+	credentialHelperTimeout  = 30 * time.Second
+	configFileRelativeToHome = ".config/yno/config.yaml"
+)
+
+// Config は YNOClient の接続設定
+type Config struct {
+	Profile       string
+	BaseURL       string
+	APIKey        string
+	MngAPIVersion string
+}
+
+// configFile は設定ファイルの内容
+//
+//	default_profile: prod
+//	profiles:
+//	  prod:
+//	    base_url: https://yno-mngapi.netvolante.jp
+//	    credential_helper: pass show yno/prod # 空白区切り。引用符は解釈されない
+//	  staging:
+//	    api_key: xxxx
+//
+// トップレベルの値は全てのプロファイルの既定値になる
+type configFile struct {
+	DefaultProfile string                   `yaml:"default_profile"`
+	Profiles       map[string]profileConfig `yaml:"profiles"`
+	profileConfig  `yaml:",inline"`
+}
+
+type profileConfig struct {
+	BaseURL          string `yaml:"base_url"`
+	APIKey           string `yaml:"api_key"`
+	MngAPIVersion    string `yaml:"mng_api_version"`
+	CredentialHelper string `yaml:"credential_helper"`
+}
+
+type configLoader struct {
+	profile    string
+	configFile string
+	explicit   profileConfig
+}
+
+type ConfigOption func(*configLoader)
+
+func WithProfile(profile string) ConfigOption {
+	return func(l *configLoader) {
+		l.profile = profile
+	}
+}
+
+func WithConfigFile(path string) ConfigOption {
+	return func(l *configLoader) {
+		l.configFile = path
+	}
+}
+
+func WithConfigBaseURL(baseURL string) ConfigOption {
+	return func(l *configLoader) {
+		l.explicit.BaseURL = baseURL
+	}
+}
+
+func WithConfigAPIKey(apiKey string) ConfigOption {
+	return func(l *configLoader) {
+		l.explicit.APIKey = apiKey
+	}
+}
+
+func WithConfigMngAPIVersion(version string) ConfigOption {
+	return func(l *configLoader) {
+		l.explicit.MngAPIVersion = version
+	}
+}
+
+// WithCredentialHelper は APIキーが他で見つからなかった場合に実行するコマンドを設定する
+// command は空白で区切って引数にする。シェルを介さないため引用符やエスケープは解釈されない
+func WithCredentialHelper(command string) ConfigOption {
+	return func(l *configLoader) {
+		l.explicit.CredentialHelper = command
+	}
+}
+
+// DefaultConfigPath は設定ファイルの既定のパス ($XDG_CONFIG_HOME/yno/config.yaml または ~/.config/yno/config.yaml) を返す
+func DefaultConfigPath() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "yno", "config.yaml")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, configFileRelativeToHome)
+}
+
+// LoadConfig は以下の順に設定を解決する。先に見つかった値が優先される
+//  1. 明示的なオプション
+//  2. 環境変数 (YNO_API_KEY, YNO_BASE_URL, YNO_MNGAPI_VERSION)
+//  3. 設定ファイルのプロファイル
+//  4. credential helper コマンドの標準出力 (APIキーのみ)
+func LoadConfig(ctx context.Context, opts ...ConfigOption) (*Config, error) {
+	l := &configLoader{}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	path := firstNonEmpty(l.configFile, os.Getenv(EnvConfigFile), DefaultConfigPath())
+	file, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	profileName := firstNonEmpty(l.profile, os.Getenv(EnvProfile), file.DefaultProfile, defaultProfile)
+	profile, ok := file.Profiles[profileName]
+	if !ok && (l.profile != "" || os.Getenv(EnvProfile) != "") {
+		return nil, fmt.Errorf("profile %q is not defined in %s", profileName, path)
+	}
+
+	env := profileConfig{
+		BaseURL:          os.Getenv(EnvBaseURL),
+		APIKey:           os.Getenv(EnvAPIKey),
+		MngAPIVersion:    os.Getenv(EnvMngAPIVersion),
+		CredentialHelper: os.Getenv(EnvCredentialHelper),
+	}
+
+	chain := []profileConfig{l.explicit, env, profile, file.profileConfig}
+
+	cfg := &Config{Profile: profileName}
+	var credentialHelper string
+	for _, c := range chain {
+		cfg.BaseURL = firstNonEmpty(cfg.BaseURL, c.BaseURL)
+		cfg.APIKey = firstNonEmpty(cfg.APIKey, c.APIKey)
+		cfg.MngAPIVersion = firstNonEmpty(cfg.MngAPIVersion, c.MngAPIVersion)
+		credentialHelper = firstNonEmpty(credentialHelper, c.CredentialHelper)
+	}
+
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = YNO_BASE_URL
+	}
+
+	if cfg.APIKey == "" && credentialHelper != "" {
+		cfg.APIKey, err = runCredentialHelper(ctx, credentialHelper, profileName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("api key is not configured for profile %q: set %s, api_key or credential_helper", profileName, EnvAPIKey)
+	}
+
+	return cfg, nil
+}
+
+func readConfigFile(path string) (*configFile, error) {
+	file := &configFile{}
+	if path == "" {
+		return file, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(b, file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return file, nil
+}
+
+// runCredentialHelper は command を実行し、標準出力の最初の行をAPIキーとして返す
+// command は strings.Fields で分割するため、空白を含む引数は渡せない (引用符は文字としてそのまま渡される)
+// 空白を含む引数が必要な場合はスクリプトにまとめて、そのパスを指定する
+// コマンドには環境変数 YNO_PROFILE でプロファイル名が渡される
+func runCredentialHelper(ctx context.Context, command, profile string) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, credentialHelperTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), EnvProfile+"="+profile)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("credential helper %q failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	apiKey, _, _ := strings.Cut(stdout.String(), "\n")
+	return strings.TrimSpace(apiKey), nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// NewClientFromConfig は cfg の設定で YNOClient を作成する
+func NewClientFromConfig(cfg *Config, opts ...client.Option) (*YNOClient, error) {
+	c, err := NewClient(cfg.BaseURL, cfg.APIKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MngAPIVersion != "" {
+		c.MngAPIVersion = cfg.MngAPIVersion
+	}
+
+	return c, nil
+}
+
+// NewClientFromProfile はプロファイル profile の設定で YNOClient を作成する
+// profile が空の場合は YNO_PROFILE、設定ファイルの default_profile、"default" の順に使う
+func NewClientFromProfile(profile string, opts ...client.Option) (*YNOClient, error) {
+	cfg, err := LoadConfig(context.Background(), WithProfile(profile))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClientFromConfig(cfg, opts...)
+}
@@ -0,0 +1,173 @@
+package yno
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testConfigFile = `default_profile: prod
+base_url: https://file.example.com
+mng_api_version: "1.1"
+profiles:
+  prod:
+    base_url: https://prod.example.com
+    api_key: prod-key
+  staging:
+    api_key: staging-key
+    mng_api_version: "1.2"
+  helper:
+    credential_helper: %s
+`
+
+// setupConfig は環境変数を空にし、credential helper を含む設定ファイルを作成してそのパスを返す
+func setupConfig(t *testing.T) string {
+	t.Helper()
+
+	for _, env := range []string{EnvAPIKey, EnvBaseURL, EnvMngAPIVersion, EnvProfile, EnvConfigFile, EnvCredentialHelper} {
+		t.Setenv(env, "")
+	}
+
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	helper := filepath.Join(dir, "helper.sh")
+	script := "#!/bin/sh\necho \"$YNO_PROFILE:$*\"\necho ignored\n"
+	if err := os.WriteFile(helper, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "config.yaml")
+	content := strings.Replace(testConfigFile, "%s", helper+" --field api_key", 1)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		opts []ConfigOption
+		want Config
+	}{
+		{
+			name: "default profile from the file",
+			want: Config{Profile: "prod", BaseURL: "https://prod.example.com", APIKey: "prod-key", MngAPIVersion: "1.1"},
+		},
+		{
+			name: "top level values are defaults for every profile",
+			opts: []ConfigOption{WithProfile("staging")},
+			want: Config{Profile: "staging", BaseURL: "https://file.example.com", APIKey: "staging-key", MngAPIVersion: "1.2"},
+		},
+		{
+			name: "environment overrides the file",
+			env:  map[string]string{EnvAPIKey: "env-key", EnvBaseURL: "https://env.example.com", EnvMngAPIVersion: "1.3"},
+			want: Config{Profile: "prod", BaseURL: "https://env.example.com", APIKey: "env-key", MngAPIVersion: "1.3"},
+		},
+		{
+			name: "explicit options override the environment",
+			env:  map[string]string{EnvAPIKey: "env-key", EnvBaseURL: "https://env.example.com"},
+			opts: []ConfigOption{WithConfigAPIKey("explicit-key"), WithConfigBaseURL("https://explicit.example.com"), WithConfigMngAPIVersion("1.4")},
+			want: Config{Profile: "prod", BaseURL: "https://explicit.example.com", APIKey: "explicit-key", MngAPIVersion: "1.4"},
+		},
+		{
+			name: "profile from the environment",
+			env:  map[string]string{EnvProfile: "staging"},
+			want: Config{Profile: "staging", BaseURL: "https://file.example.com", APIKey: "staging-key", MngAPIVersion: "1.2"},
+		},
+		{
+			name: "explicit profile overrides the environment",
+			env:  map[string]string{EnvProfile: "staging"},
+			opts: []ConfigOption{WithProfile("prod")},
+			want: Config{Profile: "prod", BaseURL: "https://prod.example.com", APIKey: "prod-key", MngAPIVersion: "1.1"},
+		},
+		{
+			name: "credential helper is run with the profile name and whitespace separated arguments",
+			opts: []ConfigOption{WithProfile("helper")},
+			want: Config{Profile: "helper", BaseURL: "https://file.example.com", APIKey: "helper:--field api_key", MngAPIVersion: "1.1"},
+		},
+		{
+			name: "credential helper is not run when an api key is found",
+			env:  map[string]string{EnvAPIKey: "env-key"},
+			opts: []ConfigOption{WithProfile("helper")},
+			want: Config{Profile: "helper", BaseURL: "https://file.example.com", APIKey: "env-key", MngAPIVersion: "1.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := setupConfig(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			got, err := LoadConfig(context.Background(), append([]ConfigOption{WithConfigFile(path)}, tt.opts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if *got != tt.want {
+				t.Errorf("LoadConfig() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigDefaults(t *testing.T) {
+	setupConfig(t)
+	t.Setenv(EnvAPIKey, "env-key")
+
+	// 設定ファイルがない場合は既定の接続先と "default" プロファイルを使う
+	got, err := LoadConfig(context.Background(), WithConfigFile(filepath.Join(t.TempDir(), "missing.yaml")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Config{Profile: defaultProfile, BaseURL: YNO_BASE_URL, APIKey: "env-key"}
+	if *got != want {
+		t.Errorf("LoadConfig() = %+v, want %+v", *got, want)
+	}
+}
+
+func TestLoadConfigCredentialHelperFromEnv(t *testing.T) {
+	path := setupConfig(t)
+	helper := filepath.Join(filepath.Dir(path), "helper.sh")
+	t.Setenv(EnvCredentialHelper, helper+" env")
+
+	got, err := LoadConfig(context.Background(), WithConfigFile(path), WithProfile("staging"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.APIKey != "staging-key" {
+		t.Errorf("APIKey = %q, want the profile api_key before the credential helper", got.APIKey)
+	}
+
+	got, err = LoadConfig(context.Background(), WithConfigFile(filepath.Join(t.TempDir(), "missing.yaml")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.APIKey != "default:env" {
+		t.Errorf("APIKey = %q, want %q", got.APIKey, "default:env")
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	path := setupConfig(t)
+
+	if _, err := LoadConfig(context.Background(), WithConfigFile(path), WithProfile("missing")); err == nil {
+		t.Error("LoadConfig() with an undefined profile succeeded")
+	}
+
+	if _, err := LoadConfig(context.Background(), WithConfigFile(filepath.Join(t.TempDir(), "missing.yaml"))); err == nil {
+		t.Error("LoadConfig() without an api key succeeded")
+	}
+
+	if _, err := LoadConfig(context.Background(), WithConfigFile(path), WithProfile("helper"), WithCredentialHelper("false")); err == nil {
+		t.Error("LoadConfig() with a failing credential helper succeeded")
+	}
+}
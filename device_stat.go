@@ -2,8 +2,9 @@ package yno
 
 import (
 	"context"
-
-	"github.com/murasame29/yno-sdk/client"
+	"encoding/json"
+	"fmt"
+	"reflect"
 )
 
 type GetDeviceStatsRequest struct {
@@ -30,7 +31,7 @@ func (r GetDeviceStatsRequest) Validate() error {
 		return ValidateErrorRequired{"StartTime"}
 	}
 
-	if *r.StartTime >= 0 {
+	if *r.StartTime < 0 {
 		return ValidateErrorNotMatch{"StartTime", ">=0"}
 	}
 
@@ -38,14 +39,29 @@ func (r GetDeviceStatsRequest) Validate() error {
 		return ValidateErrorRequired{"EndTime"}
 	}
 
-	if *r.StartTime >= 0 {
-		return ValidateErrorNotMatch{"StartTime", ">=0"}
+	if *r.EndTime < *r.StartTime {
+		return ValidateErrorNotMatch{"EndTime", ">=StartTime"}
 	}
 
 	if r.Statistics == nil {
 		return ValidateErrorRequired{"Statistics"}
 	}
 
+	if want := r.Type.newParameter(); want != nil {
+		if r.Parameters == nil {
+			return ValidateErrorRequired{"Parameters"}
+		}
+
+		got := reflect.TypeOf(r.Parameters)
+		if got.Kind() == reflect.Pointer {
+			got = got.Elem()
+		}
+
+		if wantType := reflect.TypeOf(want).Elem(); got != wantType {
+			return ValidateErrorNotMatch{"Parameters", wantType.String()}
+		}
+	}
+
 	if r.Parameters != nil {
 		return r.Parameters.Validate()
 	}
@@ -53,10 +69,65 @@ func (r GetDeviceStatsRequest) Validate() error {
 	return nil
 }
 
+func (r *GetDeviceStatsRequest) UnmarshalJSON(data []byte) error {
+	type alias GetDeviceStatsRequest
+	var raw struct {
+		alias
+		Parameters json.RawMessage `json:"Parameters,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*r = GetDeviceStatsRequest(raw.alias)
+	r.Parameters = nil
+
+	if len(raw.Parameters) == 0 || string(raw.Parameters) == "null" {
+		return nil
+	}
+
+	if r.Type == nil {
+		return ValidateErrorRequired{"Type"}
+	}
+
+	params := r.Type.newParameter()
+	if params == nil {
+		return fmt.Errorf("%s does not take Parameters", *r.Type)
+	}
+
+	if err := json.Unmarshal(raw.Parameters, params); err != nil {
+		return err
+	}
+
+	// ポインタを外して元の値型に戻す
+	r.Parameters = reflect.ValueOf(params).Elem().Interface().(Parameter)
+
+	return nil
+}
+
 type Parameter interface {
 	Validate() error
 }
 
+// newParameter は統計情報の種別に対応する Parameter のポインタを返す
+// パラメーターを取らない種別の場合は nil を返す
+func (t *DeviceStatType) newParameter() Parameter {
+	if t == nil {
+		return nil
+	}
+
+	switch *t {
+	case DeviceStatTypeCpuUtilization:
+		return &CpuUtilizationParameter{}
+	case DeviceStatTypeAmountOfTraffic:
+		return &AmountOfTrafficParameter{}
+	case DeviceStatTypeNumberOfFastPathFlows:
+		return &NumberOfFastPathFlowsParameter{}
+	}
+
+	return nil
+}
+
 type CpuUtilizationParameter struct {
 	CpuId *int `json:"CpuId,omitempty"`
 }
@@ -99,7 +170,9 @@ func (p NumberOfFastPathFlowsParameter) Validate() error {
 }
 
 type GetDeviceStatsResponse struct {
-	Meta MetaData `json:"Meta"`
+	Meta     MetaData                   `json:"Meta"`
+	Data     GetDeviceStatsResponseData `json:"Data"`
+	Warnings []Warning                  `json:"Warnings,omitempty"`
 }
 
 type GetDeviceStatsResponseData struct {
@@ -117,15 +190,16 @@ func (c *YNOClient) GetDeviceStatistic(ctx context.Context, requestBody *GetDevi
 		return nil, err
 	}
 
-	var clientOpts []client.Option
-	for _, optFunc := range opts {
-		clientOpts = optFunc(clientOpts)
-	}
+	clientOpts := c.clientOptions(opts)
 
 	var responseBody GetDeviceStatsResponse
-	err := c.client.Post(ctx, "routers/_search", requestBody, &responseBody, clientOpts...)
+	err := c.client.Post(ctx, "devicestats/_search", requestBody, &responseBody, clientOpts...)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
+	}
+
+	if err := c.checkResponse(ctx, "GetDeviceStatistic", responseBody.Meta, responseBody.Warnings); err != nil {
+		return responseOnWarning(&responseBody, err), err
 	}
 
 	return &responseBody, nil
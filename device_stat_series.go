@@ -0,0 +1,91 @@
+package yno
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// DeviceStatsMaxRange: YNOClient.DeviceStatsRange が指定されていない場合に 1 回の GetDeviceStatistic で取得する期間
+// API の上限ではなく、1リクエストの件数を抑えるための経験的な既定値 (API仕様には期間の上限が記載されていない)
+// 長い期間をまとめて取得したい場合は YNOClient.DeviceStatsRange で変更する
+const DeviceStatsMaxRange = 24 * time.Hour
+
+type DeviceStatPoint struct {
+	Time  time.Time
+	Value int
+}
+
+// DeviceStats は from から to までの統計情報を YNOClient.DeviceStatsRange (既定は DeviceStatsMaxRange) 毎に分割して取得し、
+// SearchAfter を辿って時刻順に重複を除いた系列を返す
+// params は statType が CpuUtilization, AmountOfTraffic, NumberOfFastPathFlows の場合に必要
+func (c *YNOClient) DeviceStats(ctx context.Context, serialNumber string, statType DeviceStatType, from, to time.Time, period time.Duration, statistic Statistic, params Parameter, opts ...OptionFunc) ([]DeviceStatPoint, error) {
+	if !from.Before(to) {
+		return nil, ValidateErrorNotMatch{"to", "from < to"}
+	}
+
+	var periodSeconds *int
+	if period > 0 {
+		periodSeconds = Ptr(int(period / time.Second))
+	}
+
+	window := c.DeviceStatsRange
+	if window <= 0 {
+		window = DeviceStatsMaxRange
+	}
+
+	points := make(map[int64]DeviceStatPoint)
+	for windowStart := from; windowStart.Before(to); {
+		windowEnd := windowStart.Add(window)
+		if windowEnd.After(to) {
+			windowEnd = to
+		}
+
+		requestBody := &GetDeviceStatsRequest{
+			Type:         Ptr(statType),
+			SerialNumber: Ptr(serialNumber),
+			StartTime:    Ptr(int(windowStart.Unix())),
+			EndTime:      Ptr(int(windowEnd.Unix())),
+			Period:       periodSeconds,
+			Statistics:   Ptr(statistic),
+			Parameters:   params,
+		}
+
+		for {
+			responseBody, err := c.GetDeviceStatistic(ctx, requestBody, opts...)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, stat := range responseBody.Data.DeviceStatistics {
+				points[int64(stat.Timestamp)] = DeviceStatPoint{
+					Time:  time.Unix(int64(stat.Timestamp), 0),
+					Value: stat.Value,
+				}
+			}
+
+			next := responseBody.Data.NextSearchAfter
+			if next == "" || len(responseBody.Data.DeviceStatistics) == 0 {
+				break
+			}
+
+			if requestBody.SearchAfter != nil && *requestBody.SearchAfter == next {
+				break
+			}
+			requestBody.SearchAfter = Ptr(next)
+		}
+
+		windowStart = windowEnd
+	}
+
+	series := make([]DeviceStatPoint, 0, len(points))
+	for _, point := range points {
+		series = append(series, point)
+	}
+
+	slices.SortFunc(series, func(a, b DeviceStatPoint) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	return series, nil
+}
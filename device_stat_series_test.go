@@ -0,0 +1,48 @@
+package yno_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/murasame29/yno-sdk"
+	"github.com/murasame29/yno-sdk/ynotest"
+)
+
+func TestDeviceStatsRange(t *testing.T) {
+	s := ynotest.NewServer()
+	defer s.Close()
+
+	from := time.Unix(1_700_000_000, 0)
+	to := from.Add(3 * time.Hour)
+	for i := range 6 {
+		s.AddDeviceStatistics("S1", yno.DeviceStatTypeMemoryUtilization, nil, yno.DeviceStatistic{
+			Timestamp: int(from.Add(time.Duration(i) * 30 * time.Minute).Unix()),
+			Value:     i,
+		})
+	}
+
+	c, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.DeviceStatsRange = time.Hour
+
+	series, err := c.DeviceStats(context.Background(), "S1", yno.DeviceStatTypeMemoryUtilization, from, to, 0, yno.StatisticTypeAverage, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(series) != 6 {
+		t.Errorf("DeviceStats() returned %d points, want 6", len(series))
+	}
+	for i, point := range series {
+		if point.Value != i {
+			t.Errorf("series[%d].Value = %d, want %d", i, point.Value, i)
+		}
+	}
+
+	if got := len(s.Requests()); got != 3 {
+		t.Errorf("DeviceStats() sent %d requests, want 3 one-hour windows", got)
+	}
+}
@@ -0,0 +1,72 @@
+package yno_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/murasame29/yno-sdk"
+)
+
+func TestGetDeviceStatistic(t *testing.T) {
+	var (
+		gotMethod, gotPath string
+		gotBody            yno.GetDeviceStatsRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		b, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(b, &gotBody); err != nil {
+			t.Errorf("failed to decode request body %s: %v", b, err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{
+			"Meta": {"MngApiVersion": "1.0"},
+			"Data": {
+				"NextSearchAfter": "1700000600",
+				"DeviceStatistics": [{"Ts": 1700000000, "Val": 12}, {"Ts": 1700000300, "Val": 34}]
+			}
+		}`)
+	}))
+	defer server.Close()
+
+	c, err := yno.NewClient(server.URL, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params := yno.AmountOfTrafficParameter{Direction: yno.Ptr(yno.TrafficDirectionIn), Interface: yno.Ptr("LAN1")}
+	resp, err := c.GetDeviceStatistic(context.Background(), &yno.GetDeviceStatsRequest{
+		Type:         yno.Ptr(yno.DeviceStatTypeAmountOfTraffic),
+		SerialNumber: yno.Ptr("S1"),
+		StartTime:    yno.Ptr(1700000000),
+		EndTime:      yno.Ptr(1700000600),
+		Statistics:   yno.Ptr(yno.StatisticTypeAverage),
+		Parameters:   params,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gotMethod != http.MethodPost || gotPath != "/devicestats/_search" {
+		t.Errorf("request = %s %s, want POST /devicestats/_search", gotMethod, gotPath)
+	}
+
+	if got, ok := gotBody.Parameters.(yno.AmountOfTrafficParameter); !ok || *got.Interface != "LAN1" || *got.Direction != yno.TrafficDirectionIn {
+		t.Errorf("request Parameters = %#v, want %#v", gotBody.Parameters, params)
+	}
+
+	if resp.Data.NextSearchAfter != "1700000600" {
+		t.Errorf("NextSearchAfter = %q, want 1700000600", resp.Data.NextSearchAfter)
+	}
+
+	want := []yno.DeviceStatistic{{Timestamp: 1700000000, Value: 12}, {Timestamp: 1700000300, Value: 34}}
+	if !slices.Equal(resp.Data.DeviceStatistics, want) {
+		t.Errorf("DeviceStatistics = %+v, want %+v", resp.Data.DeviceStatistics, want)
+	}
+}
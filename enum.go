@@ -70,3 +70,8 @@ const (
 	FormatOfAlarmNotificationEmailBodyJson FormatOfAlarmNotificationEmailBody = "JSON"
 	FormatOfAlarmNotificationEmailBodyText FormatOfAlarmNotificationEmailBody = "Text"
 )
+
+const (
+	// ExecuteCommand: コマンド実行タスク
+	TaskTypeExecuteCommand = "ExecuteCommand"
+)
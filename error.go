@@ -1,6 +1,14 @@
 package yno
 
-import "fmt"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/murasame29/yno-sdk/client"
+)
 
 type ValidateErrorRequired struct {
 	FieldName any
@@ -18,3 +26,120 @@ type ValidateErrorNotMatch struct {
 func (e ValidateErrorNotMatch) Error() string {
 	return fmt.Sprintf("%s does not match. %s", e.FieldName, e.Regex)
 }
+
+type ErrorDetail struct {
+	Code    string `json:"Code"`
+	Message string `json:"Message"`
+}
+
+// APIError はYNO管理APIが返したエラーレスポンス
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Errors     []ErrorDetail
+	Meta       MetaData
+	Body       string
+
+	err *client.HTTPError
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" && e.Message == "" {
+		return fmt.Sprintf("yno api error: status code %d", e.StatusCode)
+	}
+
+	return fmt.Sprintf("yno api error: status code %d, code: %s, message: %s", e.StatusCode, e.Code, e.Message)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+type errorEnvelope struct {
+	Meta    MetaData      `json:"Meta"`
+	Code    string        `json:"Code"`
+	Message string        `json:"Message"`
+	Error   *ErrorDetail  `json:"Error"`
+	Errors  []ErrorDetail `json:"Errors"`
+}
+
+func newAPIError(httpErr *client.HTTPError) *APIError {
+	apiErr := &APIError{
+		StatusCode: httpErr.StatusCode,
+		Body:       httpErr.Body,
+		err:        httpErr,
+	}
+
+	var envelope errorEnvelope
+	if err := json.Unmarshal([]byte(httpErr.Body), &envelope); err != nil {
+		return apiErr
+	}
+
+	apiErr.Meta = envelope.Meta
+	apiErr.Code = envelope.Code
+	apiErr.Message = envelope.Message
+	apiErr.Errors = envelope.Errors
+
+	if envelope.Error != nil {
+		apiErr.Errors = append([]ErrorDetail{*envelope.Error}, apiErr.Errors...)
+	}
+
+	if apiErr.Code == "" && apiErr.Message == "" && len(apiErr.Errors) > 0 {
+		apiErr.Code = apiErr.Errors[0].Code
+		apiErr.Message = apiErr.Errors[0].Message
+	}
+
+	return apiErr
+}
+
+// wrapError は client.HTTPError を APIError に変換する
+func wrapError(err error) error {
+	var httpErr *client.HTTPError
+	if !errors.As(err, &httpErr) {
+		return err
+	}
+
+	return newAPIError(httpErr)
+}
+
+func hasStatusCode(err error, statusCodes ...int) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return slices.Contains(statusCodes, apiErr.StatusCode)
+	}
+
+	var httpErr *client.HTTPError
+	if errors.As(err, &httpErr) {
+		return slices.Contains(statusCodes, httpErr.StatusCode)
+	}
+
+	return false
+}
+
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+func IsRateLimited(err error) bool {
+	return hasStatusCode(err, http.StatusTooManyRequests)
+}
+
+func IsUnauthorized(err error) bool {
+	return hasStatusCode(err, http.StatusUnauthorized, http.StatusForbidden)
+}
+
+func IsConflict(err error) bool {
+	return hasStatusCode(err, http.StatusConflict)
+}
+
+// QueryParseError はテキスト形式の検索条件の構文エラー
+type QueryParseError struct {
+	// Pos: エラーが発生した位置(文字単位、0始まり)
+	Pos int
+	Msg string
+}
+
+func (e *QueryParseError) Error() string {
+	return fmt.Sprintf("query parse error at position %d: %s", e.Pos, e.Msg)
+}
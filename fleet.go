@@ -0,0 +1,294 @@
+package yno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxTaskSerialNumbers: 1つのタスクに指定できるシリアル番号の上限
+	MaxTaskSerialNumbers = 1000
+	// MaxTaskCommands: 1つのタスクに指定できるコマンドの上限
+	MaxTaskCommands = 100
+
+	defaultFleetConcurrency = 4
+	// fleetWaitSlack: タスクの Timeout に加えて完了を待つ時間
+	// Timeout ちょうどに終了したタスクの結果を、最長のポーリング間隔の後に取得できるようにする
+	fleetWaitSlack = defaultWaitMaxInterval
+)
+
+type RunCommandsOnFleetOptions struct {
+	// Concurrency: 同時に実行するタスクの数。0 の場合は 4
+	Concurrency int
+	// Timeout: 各タスクの CreateTaskRequest.Timeout (秒)
+	Timeout *int
+	// Wait: 各タスクの完了を待つ際のオプション。SerialNumbers はタスクごとに設定される
+	Wait *WaitForTaskOptions
+}
+
+// FleetTask は RunCommandsOnFleet が作成した1つのタスク
+type FleetTask struct {
+	TaskID        string
+	SerialNumbers []string
+	Commands      []string
+	// Err: タスクの作成または完了待ちに失敗した場合のエラー
+	Err error
+}
+
+// FleetResult は RunCommandsOnFleet の結果
+type FleetResult struct {
+	// Tasks: 作成した全てのタスク
+	Tasks []FleetTask
+	// Devices: シリアル番号ごとの実行結果。複数のタスクに分割したコマンドの結果は CommandResults に順に連結される
+	Devices  map[string]DeviceTaskResult
+	Warnings []Warning
+
+	serialNumbers []string
+}
+
+// TaskIDs は作成した全てのタスクのIDを返す
+func (r *FleetResult) TaskIDs() []string {
+	ids := make([]string, 0, len(r.Tasks))
+	for _, task := range r.Tasks {
+		if task.TaskID != "" {
+			ids = append(ids, task.TaskID)
+		}
+	}
+	return ids
+}
+
+// Failed は全てのコマンドが成功しなかったシリアル番号を返す。結果を取得できなかったシリアル番号も含む
+func (r *FleetResult) Failed() []string {
+	var failed []string
+	for _, serialNumber := range r.serialNumbers {
+		if device, ok := r.Devices[serialNumber]; !ok || device.Status != ExecuteCommandStatusSuccess {
+			failed = append(failed, serialNumber)
+		}
+	}
+	return failed
+}
+
+// RunCommandsOnFleet は commands を serialNumbers の全てのルーターで実行する
+// シリアル番号は MaxTaskSerialNumbers 件ごと、コマンドは MaxTaskCommands 件ごとのタスクに分割される
+// pp select と tunnel select で選択した状態はタスクをまたいで引き継がれないため、
+// 選択中にコマンドを分割した場合は次のタスクの先頭で同じ select を実行し直す
+// シリアル番号のまとまりごとのタスクは Concurrency 件まで並行に実行し、
+// コマンドを分割したタスクは前のタスクが SUCCESS になったルーターに対してのみ順に実行する
+// 一部のタスクが失敗しても残りのタスクは続け、全てのエラーをまとめて返す
+func (c *YNOClient) RunCommandsOnFleet(ctx context.Context, serialNumbers, commands []string, options *RunCommandsOnFleetOptions, opts ...OptionFunc) (*FleetResult, error) {
+	if options == nil {
+		options = &RunCommandsOnFleetOptions{}
+	}
+
+	if len(serialNumbers) == 0 {
+		return nil, ValidateErrorRequired{"SerialNumbers"}
+	}
+
+	if len(commands) == 0 {
+		return nil, ValidateErrorRequired{"Commands"}
+	}
+
+	serialNumbers = uniqueStrings(serialNumbers)
+	return c.runFleetJobs(ctx, []fleetJob{{serialNumbers: serialNumbers, commands: commands}}, options, opts)
+}
+
+// fleetJob は同じコマンドを実行するルーターのまとまり
+type fleetJob struct {
+	serialNumbers []string
+	commands      []string
+}
+
+// runFleetJobs は jobs をタスクの上限に合わせて分割し、Concurrency 件まで並行に実行する
+func (c *YNOClient) runFleetJobs(ctx context.Context, jobs []fleetJob, options *RunCommandsOnFleetOptions, opts []OptionFunc) (*FleetResult, error) {
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultFleetConcurrency
+	}
+
+	result := &FleetResult{Devices: map[string]DeviceTaskResult{}}
+
+	type chunk struct {
+		serialNumbers []string
+		commands      []string
+		commandChunks [][]string
+	}
+	var chunks []chunk
+	for _, job := range jobs {
+		result.serialNumbers = append(result.serialNumbers, job.serialNumbers...)
+		commandChunks := splitCommands(job.commands, MaxTaskCommands)
+		for serialChunk := range slices.Chunk(job.serialNumbers, MaxTaskSerialNumbers) {
+			chunks = append(chunks, chunk{serialNumbers: serialChunk, commands: job.commands, commandChunks: commandChunks})
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	tasks := make([][]FleetTask, len(chunks))
+	sem := make(chan struct{}, concurrency)
+	for i, ch := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			tasks[i] = []FleetTask{{SerialNumbers: ch.serialNumbers, Commands: ch.commands, Err: ctx.Err()}}
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			tasks[i] = c.runCommandChunks(ctx, ch.serialNumbers, ch.commandChunks, options, result, &mu, opts)
+		}()
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, chunkTasks := range tasks {
+		for _, task := range chunkTasks {
+			result.Tasks = append(result.Tasks, task)
+			if task.Err != nil {
+				errs = append(errs, fmt.Errorf("task %s (%d routers): %w", task.TaskID, len(task.SerialNumbers), task.Err))
+			}
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// runCommandChunks は serialNumbers に対してコマンドのまとまりを順にタスクとして実行し、結果を result に追加する
+func (c *YNOClient) runCommandChunks(ctx context.Context, serialNumbers []string, commandChunks [][]string, options *RunCommandsOnFleetOptions, result *FleetResult, mu *sync.Mutex, opts []OptionFunc) []FleetTask {
+	var tasks []FleetTask
+	targets := serialNumbers
+	for _, commands := range commandChunks {
+		if len(targets) == 0 {
+			break
+		}
+
+		task := FleetTask{SerialNumbers: targets, Commands: commands}
+		taskResult, err := c.runFleetTask(ctx, &task, options, opts)
+		if taskResult != nil {
+			mu.Lock()
+			result.Warnings = append(result.Warnings, taskResult.Warnings...)
+			for _, device := range taskResult.Devices {
+				result.Devices[device.SerialNumber] = mergeDeviceTaskResult(result.Devices[device.SerialNumber], device)
+			}
+			mu.Unlock()
+		}
+
+		task.Err = err
+		tasks = append(tasks, task)
+		if err != nil {
+			break
+		}
+
+		var next []string
+		for _, serialNumber := range targets {
+			if device, ok := taskResult.Device(serialNumber); ok && device.Status == ExecuteCommandStatusSuccess {
+				next = append(next, serialNumber)
+			}
+		}
+		targets = next
+	}
+
+	return tasks
+}
+
+func (c *YNOClient) runFleetTask(ctx context.Context, task *FleetTask, options *RunCommandsOnFleetOptions, opts []OptionFunc) (*TaskResult, error) {
+	responseBody, err := c.CreateTask(ctx, &CreateTaskRequest{
+		Type:    Ptr(TaskTypeExecuteCommand),
+		Timeout: options.Timeout,
+		Parameters: &TaskParameter{
+			SerialNumbers: task.SerialNumbers,
+			Commands:      task.Commands,
+		},
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if responseBody.Data == nil {
+		return nil, errors.New("task id was not returned")
+	}
+	task.TaskID = responseBody.Data.TaskId
+
+	waitOpts := WaitForTaskOptions{}
+	if options.Wait != nil {
+		waitOpts = *options.Wait
+	}
+	waitOpts.SerialNumbers = task.SerialNumbers
+	if waitOpts.Timeout <= 0 {
+		waitOpts.Timeout = defaultTaskTimeout + fleetWaitSlack
+		if options.Timeout != nil {
+			waitOpts.Timeout = time.Duration(*options.Timeout)*time.Second + fleetWaitSlack
+		}
+	}
+
+	return c.WaitForTask(ctx, task.TaskID, &waitOpts, opts...)
+}
+
+// splitCommands は commands を size 件ごとに分割する
+// pp select N または tunnel select N で選択している間に分割した場合は、次のまとまりの先頭に同じ select を加える
+func splitCommands(commands []string, size int) [][]string {
+	var (
+		chunks   [][]string
+		current  []string
+		selected = map[string]string{}
+	)
+	for _, command := range commands {
+		if len(current) == size {
+			chunks = append(chunks, current)
+			current = nil
+			for _, kind := range []string{"pp", "tunnel"} {
+				if sel, ok := selected[kind]; ok {
+					current = append(current, sel)
+				}
+			}
+		}
+
+		current = append(current, command)
+
+		fields := strings.Fields(strings.ToLower(command))
+		if len(fields) == 3 && (fields[0] == "pp" || fields[0] == "tunnel") && fields[1] == "select" {
+			if fields[2] == "none" {
+				delete(selected, fields[0])
+			} else {
+				selected[fields[0]] = command
+			}
+		}
+	}
+
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+
+	return chunks
+}
+
+// mergeDeviceTaskResult は同じルーターの後続のタスクの結果を前の結果に連結する
+func mergeDeviceTaskResult(prev, next DeviceTaskResult) DeviceTaskResult {
+	if prev.SerialNumber == "" {
+		return next
+	}
+
+	prev.Status = next.Status
+	prev.CommandResults = append(prev.CommandResults, next.CommandResults...)
+	return prev
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
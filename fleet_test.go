@@ -0,0 +1,73 @@
+package yno
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestSplitCommands(t *testing.T) {
+	commands := func(prefix string, n int) []string {
+		var cmds []string
+		for i := range n {
+			cmds = append(cmds, fmt.Sprintf("%s %d", prefix, i))
+		}
+		return cmds
+	}
+
+	tests := []struct {
+		name     string
+		commands []string
+		size     int
+		want     [][]string
+	}{
+		{
+			name:     "fits in one task",
+			commands: []string{"a", "b"},
+			size:     3,
+			want:     [][]string{{"a", "b"}},
+		},
+		{
+			name:     "no select",
+			commands: []string{"a", "b", "c"},
+			size:     2,
+			want:     [][]string{{"a", "b"}, {"c"}},
+		},
+		{
+			name:     "re-issues pp select",
+			commands: []string{"pp select 1", "a", "b", "c", "pp select none", "d"},
+			size:     3,
+			want:     [][]string{{"pp select 1", "a", "b"}, {"pp select 1", "c", "pp select none"}, {"d"}},
+		},
+		{
+			name:     "re-issues pp and tunnel select",
+			commands: []string{"pp select 1", "tunnel select 2", "a", "b"},
+			size:     3,
+			want:     [][]string{{"pp select 1", "tunnel select 2", "a"}, {"pp select 1", "tunnel select 2", "b"}},
+		},
+		{
+			name:     "select none at boundary",
+			commands: []string{"tunnel select 1", "a", "tunnel select none", "b"},
+			size:     3,
+			want:     [][]string{{"tunnel select 1", "a", "tunnel select none"}, {"b"}},
+		},
+		{
+			name:     "large block",
+			commands: slices.Concat([]string{"pp select 1"}, commands("x", 150)),
+			size:     MaxTaskCommands,
+			want: [][]string{
+				slices.Concat([]string{"pp select 1"}, commands("x", 99)),
+				slices.Concat([]string{"pp select 1"}, commands("x", 150)[99:]),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitCommands(tt.commands, tt.size)
+			if !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("splitCommands() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
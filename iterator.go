@@ -0,0 +1,98 @@
+package yno
+
+import (
+	"context"
+	"iter"
+)
+
+// AllRouters は NextPageToken を辿りながら query に一致する全ルーターを返す
+func (c *YNOClient) AllRouters(ctx context.Context, query *SearchRouterQuery, opts ...OptionFunc) iter.Seq2[RouterResponseRouter, error] {
+	return func(yield func(RouterResponseRouter, error) bool) {
+		requestBody := &SearchRouterRequest{Query: query}
+		for {
+			responseBody, err := c.SearchRotuer(ctx, requestBody, opts...)
+			if err != nil {
+				yield(RouterResponseRouter{}, err)
+				return
+			}
+
+			for _, router := range responseBody.Data.Routers {
+				if !yield(router, nil) {
+					return
+				}
+			}
+
+			if responseBody.Data.NextPageToken == "" {
+				return
+			}
+			requestBody.PageToken = Ptr(responseBody.Data.NextPageToken)
+		}
+	}
+}
+
+// AllUsers は NextPageToken を辿りながら query に一致する全ユーザーを返す
+func (c *YNOClient) AllUsers(ctx context.Context, query *SearchUserQuery, opts ...OptionFunc) iter.Seq2[UserResponseUser, error] {
+	return func(yield func(UserResponseUser, error) bool) {
+		requestBody := &SearchUserRequest{Query: query}
+		for {
+			responseBody, err := c.SearchUser(ctx, requestBody, opts...)
+			if err != nil {
+				yield(UserResponseUser{}, err)
+				return
+			}
+
+			for _, user := range responseBody.Data.Users {
+				if !yield(user, nil) {
+					return
+				}
+			}
+
+			if responseBody.Data.NextPageToken == "" {
+				return
+			}
+			requestBody.PageToken = Ptr(responseBody.Data.NextPageToken)
+		}
+	}
+}
+
+// AllTaskDevices は NextPageToken を辿りながらタスクの全デバイスの実行結果を返す
+func (c *YNOClient) AllTaskDevices(ctx context.Context, taskID string, opts ...OptionFunc) iter.Seq2[DeviceTaskResult, error] {
+	return func(yield func(DeviceTaskResult, error) bool) {
+		requestQuery := &GetExecuteTaskQuery{}
+		for {
+			responseBody, err := c.GetExecuteTask(ctx, taskID, requestQuery, opts...)
+			if err != nil {
+				yield(DeviceTaskResult{}, err)
+				return
+			}
+
+			for _, device := range responseBody.Data.Results.Devices {
+				if !yield(device, nil) {
+					return
+				}
+			}
+
+			if responseBody.Data.Results.NextPageToken == "" {
+				return
+			}
+			requestQuery.PageToken = Ptr(responseBody.Data.Results.NextPageToken)
+		}
+	}
+}
+
+// Collect は seq を最大 max 件までスライスに詰めて返す。max が 0 以下の場合は上限なし
+func Collect[T any](seq iter.Seq2[T, error], max int) ([]T, error) {
+	var items []T
+	for item, err := range seq {
+		if err != nil {
+			return items, err
+		}
+
+		items = append(items, item)
+		if max > 0 && len(items) >= max {
+			break
+		}
+	}
+
+	return items, nil
+}
@@ -0,0 +1,231 @@
+package yno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+var (
+	// ErrRouterNotFound はシリアル番号に一致するルーターが存在しない場合に返される
+	ErrRouterNotFound = errors.New("yno: router not found")
+	// ErrEmptyAssignment は AssignedLabels または AssignedUsers が空になる変更を行おうとした場合に返される
+	// YNO管理APIは空のリストによる更新を受け付けない
+	ErrEmptyAssignment = errors.New("yno: assigned labels and users cannot be updated to an empty list")
+)
+
+// LabelChangeResult は ApplyLabels の結果
+type LabelChangeResult struct {
+	// Updated: ラベルを変更したルーター (変更後)
+	Updated []RouterResponseRouter
+	// Unchanged: 既に全てのラベルが付与されていたルーターのシリアル番号
+	Unchanged []string
+	// Failed: 更新に失敗したルーターのシリアル番号とエラー
+	Failed map[string]error
+}
+
+// ListLabels はテナント内の全てのルーターに付与されているラベルを重複なく昇順で返す
+func (c *YNOClient) ListLabels(ctx context.Context, opts ...OptionFunc) ([]string, error) {
+	var labels []string
+	for router, err := range c.AllRouters(ctx, nil, opts...) {
+		if err != nil {
+			return nil, err
+		}
+
+		for _, label := range router.AssignedLabels {
+			if !slices.Contains(labels, label) {
+				labels = append(labels, label)
+			}
+		}
+	}
+
+	slices.Sort(labels)
+	return labels, nil
+}
+
+// GetRouter はシリアル番号が serialNumber のルーターを返す
+// 存在しない場合は ErrRouterNotFound を返す
+func (c *YNOClient) GetRouter(ctx context.Context, serialNumber string, opts ...OptionFunc) (*RouterResponseRouter, error) {
+	requestBody := &SearchRouterRequest{
+		Query: &SearchRouterQuery{
+			Where: &SearchRouterWhere{Equal: &SearchRouterEqualObject{SerialNumber: serialNumber}},
+		},
+	}
+
+	responseBody, err := c.SearchRotuer(ctx, requestBody, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, router := range responseBody.Data.Routers {
+		if router.SerialNumber == serialNumber {
+			return &router, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrRouterNotFound, serialNumber)
+}
+
+// AddRouterLabels はルーターに labels を追加する
+// 既存のラベルは保持され、既に付与されているラベルは無視される
+func (c *YNOClient) AddRouterLabels(ctx context.Context, serialNumber string, labels []string, opts ...OptionFunc) (*RouterResponseRouter, error) {
+	return c.modifyRouterLabels(ctx, serialNumber, labels, func(current []string) []string {
+		for _, label := range labels {
+			if !slices.Contains(current, label) {
+				current = append(current, label)
+			}
+		}
+		return current
+	}, opts...)
+}
+
+// RemoveRouterLabels はルーターから labels を取り除く
+// 全てのラベルを取り除くことはできず、その場合は ErrEmptyAssignment を返す
+func (c *YNOClient) RemoveRouterLabels(ctx context.Context, serialNumber string, labels []string, opts ...OptionFunc) (*RouterResponseRouter, error) {
+	return c.modifyRouterLabels(ctx, serialNumber, labels, func(current []string) []string {
+		return slices.DeleteFunc(current, func(label string) bool {
+			return slices.Contains(labels, label)
+		})
+	}, opts...)
+}
+
+// modifyRouterLabels はルーターのラベルを読み込み、modify で変更して書き戻す
+func (c *YNOClient) modifyRouterLabels(ctx context.Context, serialNumber string, labels []string, modify func(current []string) []string, opts ...OptionFunc) (*RouterResponseRouter, error) {
+	if len(labels) == 0 || slices.Contains(labels, "") {
+		return nil, ValidateErrorNotMatch{"labels", "not empty"}
+	}
+
+	return c.modifyRouter(ctx, serialNumber, func(router *RouterResponseRouter) (*RouterAssignedObject, error) {
+		updated := modify(slices.Clone(router.AssignedLabels))
+		if slices.Equal(updated, router.AssignedLabels) {
+			return nil, nil
+		}
+
+		if len(updated) == 0 {
+			return nil, fmt.Errorf("%w: removing labels %v from %s", ErrEmptyAssignment, labels, serialNumber)
+		}
+
+		return &RouterAssignedObject{AssignedLabels: updated}, nil
+	}, opts...)
+}
+
+// modifyRouter はルーターを読み込み、modify が返した内容で更新する
+// modify が nil を返した場合は更新せずに読み込んだルーターを返す
+// 同じ YNOClient からの同じルーターへの変更は直列化される
+func (c *YNOClient) modifyRouter(ctx context.Context, serialNumber string, modify func(router *RouterResponseRouter) (*RouterAssignedObject, error), opts ...OptionFunc) (*RouterResponseRouter, error) {
+	if serialNumber == "" {
+		return nil, ValidateErrorRequired{"SerialNumber"}
+	}
+
+	unlock := c.routerLocks.lock(serialNumber)
+	defer unlock()
+
+	router, err := c.GetRouter(ctx, serialNumber, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	requestBody, err := modify(router)
+	if err != nil {
+		return nil, err
+	}
+
+	if requestBody == nil {
+		return router, nil
+	}
+
+	responseBody, err := c.UpdateRotuer(ctx, serialNumber, requestBody, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responseBody.Data, nil
+}
+
+// ApplyLabels は where に一致する全てのルーターに labels を追加する
+// 一部のルーターの更新に失敗しても残りのルーターの更新は続け、失敗したルーターは Failed に含める
+func (c *YNOClient) ApplyLabels(ctx context.Context, where SearchRouterWhere, labels []string, opts ...OptionFunc) (*LabelChangeResult, error) {
+	if len(labels) == 0 || slices.Contains(labels, "") {
+		return nil, ValidateErrorNotMatch{"labels", "not empty"}
+	}
+
+	// 更新中に検索結果が変わらないよう、先に対象を全て取得する
+	routers, err := Collect(c.AllRouters(ctx, &SearchRouterQuery{Where: &where}, opts...), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &LabelChangeResult{Failed: map[string]error{}}
+	var errs []error
+	for _, router := range routers {
+		if hasAll(router.AssignedLabels, labels) {
+			result.Unchanged = append(result.Unchanged, router.SerialNumber)
+			continue
+		}
+
+		updated, err := c.AddRouterLabels(ctx, router.SerialNumber, labels, opts...)
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+
+			result.Failed[router.SerialNumber] = err
+			errs = append(errs, fmt.Errorf("%s: %w", router.SerialNumber, err))
+			continue
+		}
+
+		result.Updated = append(result.Updated, *updated)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+func hasAll(items, want []string) bool {
+	for _, v := range want {
+		if !slices.Contains(items, v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// keyedMutex はキーごとの排他制御を行う。ゼロ値で利用できる
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+func (m *keyedMutex) lock(key string) (unlock func()) {
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = map[string]*keyedLock{}
+	}
+	l, ok := m.locks[key]
+	if !ok {
+		l = &keyedLock{}
+		m.locks[key] = l
+	}
+	l.refs++
+	m.mu.Unlock()
+
+	l.mu.Lock()
+
+	return func() {
+		l.mu.Unlock()
+
+		m.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(m.locks, key)
+		}
+		m.mu.Unlock()
+	}
+}
@@ -10,7 +10,7 @@ type OptionFunc func([]client.Option) []client.Option
 
 func WithMngAPIVersion(version string) OptionFunc {
 	return func(o []client.Option) []client.Option {
-		return append(o, client.WithHeader("X-Yamaha-YNO-MngAPI-Version", version))
+		return append(o, client.WithHeader(mngAPIVersionHeader, version))
 	}
 }
 
@@ -25,3 +25,10 @@ func WithTimeout(timeout time.Duration) OptionFunc {
 		return append(o, client.WithTimeout(timeout))
 	}
 }
+
+// WithRetryNonIdempotent は routers/_search のような POST リクエストも再試行の対象にする
+func WithRetryNonIdempotent() OptionFunc {
+	return func(o []client.Option) []client.Option {
+		return append(o, client.WithRetryNonIdempotent())
+	}
+}
@@ -0,0 +1,128 @@
+package parsers
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Environment は show environment の出力
+//
+//	RTX1210 BootROM Ver. 1.04
+//	RTX1210 Rev.14.01.38 (Fri Jul  1 11:44:09 2020)
+//	  main:  RTX1210 ver=00 serial=S00000000 MAC-Address=00:a0:de:00:00:01 MAC-Address=00:a0:de:00:00:02
+//	CPU:   3%(5sec)   2%(1min)   2%(5min)    Memory: 28% used
+//	Packet Buffer: 0%(small) 0%(middle) 6%(large) 0%(huge) used
+//	Firmware: exec0
+//	Inside Temperature(C.): 47
+//	Startup: 2024/01/01 12:00:00 +09:00
+//	Current: 2024/01/02 12:00:00 +09:00
+//	Elapsed time from boot: 1days 00:00:00
+type Environment struct {
+	Model        string
+	BootROM      string
+	Revision     string
+	SerialNumber string
+	MACAddresses []string
+	CPU5Sec      float64
+	CPU1Min      float64
+	CPU5Min      float64
+	MemoryUsed   float64
+	PacketBuffer map[string]float64
+	Firmware     string
+	Temperature  float64
+	Startup      string
+	Current      string
+	Uptime       time.Duration
+	// Fields: 上記以外の "項目: 値" 形式の行
+	Fields map[string]string
+}
+
+var (
+	revisionRegex     = regexp.MustCompile(`^(\S+)\s+Rev\.(\S+)`)
+	bootROMRegex      = regexp.MustCompile(`^(\S+)\s+BootROM\s+Ver\.\s*(\S+)`)
+	serialRegex       = regexp.MustCompile(`serial=(\S+)`)
+	macRegex          = regexp.MustCompile(`MAC-Address=(\S+)`)
+	cpuRegex          = regexp.MustCompile(`(\d+(?:\.\d+)?)%\((5sec|1min|5min)\)`)
+	memoryRegex       = regexp.MustCompile(`(?:Memory|メモリ)\s*[:：]\s*(\d+(?:\.\d+)?)%`)
+	packetBufferRegex = regexp.MustCompile(`(\d+(?:\.\d+)?)%\((\w+)\)`)
+)
+
+// ParseEnvironment は show environment の出力を解析する
+func ParseEnvironment(output []string) (*Environment, error) {
+	lines := nonEmptyLines(output)
+	if len(lines) == 0 {
+		return nil, errors.New("empty output")
+	}
+
+	env := &Environment{Fields: map[string]string{}}
+	for _, line := range lines {
+		if m := bootROMRegex.FindStringSubmatch(line); m != nil {
+			env.Model, env.BootROM = m[1], m[2]
+			continue
+		}
+
+		if m := revisionRegex.FindStringSubmatch(line); m != nil {
+			env.Model, env.Revision = m[1], m[2]
+			continue
+		}
+
+		if strings.Contains(line, "serial=") || strings.Contains(line, "MAC-Address=") {
+			if m := serialRegex.FindStringSubmatch(line); m != nil {
+				env.SerialNumber = m[1]
+			}
+			for _, m := range macRegex.FindAllStringSubmatch(line, -1) {
+				env.MACAddresses = append(env.MACAddresses, m[1])
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, "CPU") {
+			for _, m := range cpuRegex.FindAllStringSubmatch(line, -1) {
+				v := numbers(m[1])[0]
+				switch m[2] {
+				case "5sec":
+					env.CPU5Sec = v
+				case "1min":
+					env.CPU1Min = v
+				case "5min":
+					env.CPU5Min = v
+				}
+			}
+			if m := memoryRegex.FindStringSubmatch(line); m != nil {
+				env.MemoryUsed = numbers(m[1])[0]
+			}
+			continue
+		}
+
+		key, value, ok := splitField(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case matchKey(key, "Packet Buffer", "パケットバッファ"):
+			env.PacketBuffer = map[string]float64{}
+			for _, m := range packetBufferRegex.FindAllStringSubmatch(value, -1) {
+				env.PacketBuffer[m[2]] = numbers(m[1])[0]
+			}
+		case matchKey(key, "Firmware", "ファームウェア"):
+			env.Firmware = value
+		case strings.HasPrefix(normalizeKey(key), "insidetemperature"), strings.HasPrefix(normalizeKey(key), "筐体内温度"):
+			if v := numbers(value); len(v) > 0 {
+				env.Temperature = v[0]
+			}
+		case matchKey(key, "Startup", "起動時刻"):
+			env.Startup = value
+		case matchKey(key, "Current", "現在の時刻"):
+			env.Current = value
+		case matchKey(key, "Elapsed time from boot", "起動からの経過時間"):
+			env.Uptime, _ = parseElapsed(value)
+		default:
+			env.Fields[key] = value
+		}
+	}
+
+	return env, nil
+}
@@ -0,0 +1,118 @@
+package parsers
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// IPsecSA は show ipsec sa の1行
+//
+//	Total: isakmp:2 send:1 recv:1
+//
+//	sa   sgw isakmp connection    dir  life[s] remote-id
+//	-----------------------------------------------------------------------------
+//	1     1    -    isakmp        -    28761   203.0.113.1
+//	2     1    1    tun[0001]esp  send 28763   203.0.113.1
+type IPsecSA struct {
+	SA int
+	// Gateway: セキュリティゲートウェイの番号
+	Gateway int
+	// ISAKMP: 対応する ISAKMP SA の番号。ISAKMP SA 自身の場合は 0
+	ISAKMP     int
+	Connection string
+	// Direction: send または recv。ISAKMP SA の場合は空
+	Direction string
+	// Lifetime: 残り寿命 (秒)
+	Lifetime int
+	RemoteID string
+}
+
+// IPsecSAs は show ipsec sa の出力
+type IPsecSAs struct {
+	TotalISAKMP int
+	TotalSend   int
+	TotalRecv   int
+	SAs         []IPsecSA
+}
+
+var ipsecTotalRegex = regexp.MustCompile(`(isakmp|send|recv)\s*:\s*(\d+)`)
+
+// ParseIPsecSA は show ipsec sa の出力を解析する
+func ParseIPsecSA(output []string) (*IPsecSAs, error) {
+	sas := &IPsecSAs{}
+	for _, line := range nonEmptyLines(output) {
+		if strings.HasPrefix(line, "Total") || strings.HasPrefix(line, "合計") {
+			for _, m := range ipsecTotalRegex.FindAllStringSubmatch(line, -1) {
+				n, _ := strconv.Atoi(m[2])
+				switch m[1] {
+				case "isakmp":
+					sas.TotalISAKMP = n
+				case "send":
+					sas.TotalSend = n
+				case "recv":
+					sas.TotalRecv = n
+				}
+			}
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 6 {
+			continue
+		}
+
+		sa, err := strconv.Atoi(fields[0])
+		if err != nil {
+			// 見出しと区切り線
+			continue
+		}
+
+		entry := IPsecSA{
+			SA:         sa,
+			Gateway:    atoiOrZero(fields[1]),
+			ISAKMP:     atoiOrZero(fields[2]),
+			Connection: fields[3],
+			Direction:  fields[4],
+			Lifetime:   atoiOrZero(fields[5]),
+		}
+		if entry.Direction == "-" {
+			entry.Direction = ""
+		}
+		if len(fields) > 6 {
+			entry.RemoteID = fields[6]
+		}
+
+		sas.SAs = append(sas.SAs, entry)
+	}
+
+	return sas, nil
+}
+
+// Tunnels は接続が tun[NNNN]esp の SA をトンネル番号ごとに返す
+func (s *IPsecSAs) Tunnels() map[int][]IPsecSA {
+	tunnels := map[int][]IPsecSA{}
+	for _, sa := range s.SAs {
+		if n, ok := tunnelNumber(sa.Connection); ok {
+			tunnels[n] = append(tunnels[n], sa)
+		}
+	}
+	return tunnels
+}
+
+var tunnelRegex = regexp.MustCompile(`tun\[(\d+)\]`)
+
+func tunnelNumber(connection string) (int, bool) {
+	m := tunnelRegex.FindStringSubmatch(connection)
+	if m == nil {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(m[1])
+	return n, err == nil
+}
+
+func atoiOrZero(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,73 @@
+package parsers
+
+import (
+	"errors"
+	"strings"
+)
+
+// LANStatus は show status lanN の出力
+//
+//	LAN1
+//	Description:
+//	IP Address:                 192.168.100.1/24
+//	Ethernet address:           00:a0:de:00:00:01
+//	Operation mode setting:     Auto Negotiation (1000BASE-T Full Duplex)
+//	Maximum Transmission Unit(MTU): 1500 octets
+//	Promiscuous mode:           OFF
+//	Transmitted:                1234 packets (567890 octets)
+//	Received:                   2345 packets (678901 octets)
+type LANStatus struct {
+	Interface       string
+	Description     string
+	IPAddresses     []string
+	EthernetAddress string
+	OperationMode   string
+	MTU             int64
+	Promiscuous     bool
+	Transmitted     Counter
+	Received        Counter
+	// Fields: 上記以外の "項目: 値" 形式の行
+	Fields map[string]string
+}
+
+// ParseLANStatus は show status lanN の出力を解析する
+func ParseLANStatus(output []string) (*LANStatus, error) {
+	lines := nonEmptyLines(output)
+	if len(lines) == 0 {
+		return nil, errors.New("empty output")
+	}
+
+	status := &LANStatus{Fields: map[string]string{}}
+	for i, line := range lines {
+		key, value, ok := splitField(line)
+		if !ok {
+			if i == 0 && strings.HasPrefix(strings.ToUpper(line), "LAN") {
+				status.Interface = line
+			}
+			continue
+		}
+
+		switch {
+		case matchKey(key, "Description", "説明"):
+			status.Description = value
+		case matchKey(key, "IP Address", "IPアドレス"):
+			status.IPAddresses = append(status.IPAddresses, strings.Fields(value)...)
+		case matchKey(key, "Ethernet address", "イーサネットアドレス"):
+			status.EthernetAddress = value
+		case matchKey(key, "Operation mode setting", "動作モード設定"):
+			status.OperationMode = value
+		case matchKey(key, "Maximum Transmission Unit(MTU)", "最大パケット長(MTU)"):
+			status.MTU, _ = firstInt(value)
+		case matchKey(key, "Promiscuous mode", "プロミスキャスモード"):
+			status.Promiscuous = strings.EqualFold(value, "ON")
+		case matchKey(key, "Transmitted", "送信パケット"):
+			status.Transmitted = packetCounter(value)
+		case matchKey(key, "Received", "受信パケット"):
+			status.Received = packetCounter(value)
+		default:
+			status.Fields[key] = value
+		}
+	}
+
+	return status, nil
+}
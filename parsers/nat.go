@@ -0,0 +1,135 @@
+package parsers
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NATDescriptor は show nat descriptor address の1つのNATディスクリプタ
+//
+//	Reference NAT descriptor : 1000, Interface : PP[01](1)
+//	Masquerade type: nat-masquerade
+//	Outer address: ipcp(203.0.113.10)
+//	  protocol            inner address   outer port  ttl(s)      type
+//	       TCP     192.168.100.10.52015        28003     884   dynamic
+//	       UDP       192.168.100.11.123        27013     600   dynamic
+//	2 entries.
+type NATDescriptor struct {
+	Descriptor   int
+	Interface    string
+	Type         string
+	OuterAddress string
+	// EntryCount: 出力に表示された総エントリー数。表示されていない場合は Entries の数
+	EntryCount int
+	Entries    []NATEntry
+	// Fields: 上記以外の "項目: 値" 形式の行
+	Fields map[string]string
+}
+
+// NATEntry はIPマスカレードの変換テーブルの1行
+type NATEntry struct {
+	Protocol     string
+	InnerAddress string
+	InnerPort    int
+	OuterPort    int
+	TTL          int
+	Type         string
+}
+
+// NATDescriptorAddress は show nat descriptor address の出力
+type NATDescriptorAddress struct {
+	Descriptors []NATDescriptor
+}
+
+var (
+	natDescriptorRegex = regexp.MustCompile(`(?i)(?:NAT descriptor|NATディスクリプタ)\s*[:：]\s*(\d+)(?:\s*,\s*(?:Interface|適用インタフェース)\s*[:：]\s*(\S+))?`)
+	natEntriesRegex    = regexp.MustCompile(`(?i)^(\d+)\s*(?:entries|entry|エントリ)`)
+)
+
+// ParseNATDescriptorAddress は show nat descriptor address の出力を解析する
+func ParseNATDescriptorAddress(output []string) (*NATDescriptorAddress, error) {
+	result := &NATDescriptorAddress{}
+
+	var current *NATDescriptor
+	flush := func() {
+		if current == nil {
+			return
+		}
+		if current.EntryCount == 0 {
+			current.EntryCount = len(current.Entries)
+		}
+		result.Descriptors = append(result.Descriptors, *current)
+		current = nil
+	}
+
+	for _, line := range nonEmptyLines(output) {
+		if m := natDescriptorRegex.FindStringSubmatch(line); m != nil {
+			flush()
+			current = &NATDescriptor{Descriptor: atoiOrZero(m[1]), Interface: m[2], Fields: map[string]string{}}
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		if m := natEntriesRegex.FindStringSubmatch(line); m != nil {
+			current.EntryCount = atoiOrZero(m[1])
+			continue
+		}
+
+		if entry, ok := parseNATEntry(line); ok {
+			current.Entries = append(current.Entries, entry)
+			continue
+		}
+
+		key, value, ok := splitField(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case matchKey(key, "Masquerade type", "Masqueradeタイプ", "タイプ"):
+			current.Type = value
+		case matchKey(key, "Outer address", "外側アドレス"):
+			current.OuterAddress = value
+		default:
+			current.Fields[key] = value
+		}
+	}
+
+	flush()
+	return result, nil
+}
+
+func parseNATEntry(line string) (NATEntry, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || !isProtocol(fields[0]) {
+		return NATEntry{}, false
+	}
+
+	entry := NATEntry{Protocol: fields[0], OuterPort: atoiOrZero(fields[2]), TTL: atoiOrZero(fields[3])}
+
+	// 内側アドレスは "192.168.100.10.52015" のようにポート番号が末尾に付く
+	entry.InnerAddress = fields[1]
+	if i := strings.LastIndex(fields[1], "."); i >= 0 && strings.Count(fields[1], ".") == 4 {
+		if port, err := strconv.Atoi(fields[1][i+1:]); err == nil {
+			entry.InnerAddress, entry.InnerPort = fields[1][:i], port
+		}
+	}
+
+	if len(fields) > 4 {
+		entry.Type = fields[4]
+	}
+
+	return entry, true
+}
+
+func isProtocol(s string) bool {
+	switch strings.ToUpper(s) {
+	case "TCP", "UDP", "ICMP", "GRE", "ESP", "AH", "IPIP", "OTHER":
+		return true
+	}
+	return false
+}
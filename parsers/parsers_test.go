@@ -0,0 +1,115 @@
+package parsers
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func TestParseGolden(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+	}{
+		{name: "lan_en", command: "show status lan1"},
+		{name: "lan_ja", command: "show status lan1"},
+		{name: "environment_en", command: "show environment"},
+		{name: "environment_ja", command: "show environment"},
+		{name: "route_en", command: "show ip route"},
+		{name: "route_ja", command: "show ip route"},
+		{name: "ipsec_sa", command: "show ipsec sa"},
+		{name: "nat_descriptor_address", command: "show nat descriptor address"},
+		{name: "pp_en", command: "show status pp"},
+		{name: "pp_ja", command: "show status pp 1"},
+	}
+
+	covered := map[string]bool{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join("testdata", tt.name+".txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			v, err := Parse(tt.command, strings.Split(string(input), "\n"))
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.command, err)
+			}
+
+			got, err := json.MarshalIndent(v, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			golden := filepath.Join("testdata", tt.name+".golden.json")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(got) != string(want) {
+				t.Errorf("Parse(%q) mismatch (-want +got):\n%s\n---\n%s", tt.command, want, got)
+			}
+		})
+
+		covered[lookupPrefix(tt.command)] = true
+	}
+
+	for _, e := range registry.entries {
+		if !covered[e.prefix] {
+			t.Errorf("no golden test for registered parser %q", e.prefix)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{command: "show status lan1", want: "show status lan"},
+		{command: "SHOW  status   LAN2", want: "show status lan"},
+		{command: "show status pp", want: "show status pp"},
+		{command: "show status pp 1", want: "show status pp"},
+		{command: "show status pptp", want: ""},
+		{command: "show status pptp 1", want: ""},
+		{command: "show ip route summary", want: "show ip route"},
+		{command: "show ip routes", want: ""},
+		{command: "show environment", want: "show environment"},
+	}
+
+	for _, tt := range tests {
+		if got := lookupPrefix(tt.command); got != tt.want {
+			t.Errorf("Lookup(%q) matched %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+// lookupPrefix は Lookup が command に使う prefix を返す
+func lookupPrefix(command string) string {
+	command = normalizeCommand(command)
+
+	var found string
+	for _, e := range registry.entries {
+		if matchPrefix(command, e.prefix) && len(e.prefix) > len(found) {
+			found = e.prefix
+		}
+	}
+
+	if _, ok := Lookup(command); ok != (found != "") {
+		return "<mismatch>"
+	}
+	return found
+}
@@ -0,0 +1,117 @@
+package parsers
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// PPStatus は show status pp の1つのPPインタフェース
+//
+//	PP[01]:
+//	Description: FLETS
+//	PPPoE session is connected.
+//	Access Concentrator: BAS01
+//	Communication Time: 3days 04:05:06
+//	Received: 123456 packets [12345678 octets]  Load: 0.0%
+//	Transmitted: 234567 packets [23456789 octets]  Load: 0.0%
+//	LCP Status: Opened
+//	IPCP Status: Opened
+//	  Local Address: 203.0.113.10, Remote Address: 203.0.113.1
+type PPStatus struct {
+	PP                 string
+	Description        string
+	Connected          bool
+	AccessConcentrator string
+	CommunicationTime  time.Duration
+	Received           Counter
+	Transmitted        Counter
+	LCPStatus          string
+	IPCPStatus         string
+	LocalAddress       string
+	RemoteAddress      string
+	// Fields: 上記以外の "項目: 値" 形式の行
+	Fields map[string]string
+}
+
+// PPStatuses は show status pp の出力
+type PPStatuses struct {
+	PPs []PPStatus
+}
+
+var (
+	ppHeaderRegex     = regexp.MustCompile(`^PP\[(\d+)\]\s*[:：]?$`)
+	ppLocalAddrRegex  = regexp.MustCompile(`(?:Local Address|自分側アドレス)\s*[:：]\s*([^,\s]+)`)
+	ppRemoteAddrRegex = regexp.MustCompile(`(?:Remote Address|相手側アドレス)\s*[:：]\s*([^,\s]+)`)
+)
+
+// ParsePPStatus は show status pp の出力を解析する
+func ParsePPStatus(output []string) (*PPStatuses, error) {
+	result := &PPStatuses{}
+
+	var current *PPStatus
+	flush := func() {
+		if current != nil {
+			result.PPs = append(result.PPs, *current)
+		}
+		current = nil
+	}
+
+	for _, line := range nonEmptyLines(output) {
+		if m := ppHeaderRegex.FindStringSubmatch(line); m != nil {
+			flush()
+			current = &PPStatus{PP: m[1], Fields: map[string]string{}}
+			continue
+		}
+
+		if current == nil {
+			// "show status pp 1" のように見出しがない場合
+			current = &PPStatus{Fields: map[string]string{}}
+		}
+
+		lower := strings.ToLower(line)
+		switch {
+		case strings.Contains(lower, "is connected") || strings.Contains(line, "接続されています"):
+			current.Connected = true
+			continue
+		case strings.Contains(lower, "not connected") || strings.Contains(line, "接続されていません"):
+			current.Connected = false
+			continue
+		}
+
+		if m := ppLocalAddrRegex.FindStringSubmatch(line); m != nil {
+			current.LocalAddress = m[1]
+			if m := ppRemoteAddrRegex.FindStringSubmatch(line); m != nil {
+				current.RemoteAddress = m[1]
+			}
+			continue
+		}
+
+		key, value, ok := splitField(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case matchKey(key, "Description", "説明"):
+			current.Description = value
+		case matchKey(key, "Access Concentrator", "接続先"):
+			current.AccessConcentrator = value
+		case matchKey(key, "Communication Time", "通信時間"):
+			current.CommunicationTime, _ = parseElapsed(value)
+		case matchKey(key, "Received", "受信"):
+			current.Received = packetCounter(strings.Split(value, "Load")[0])
+		case matchKey(key, "Transmitted", "送信"):
+			current.Transmitted = packetCounter(strings.Split(value, "Load")[0])
+		case matchKey(key, "LCP Status", "LCP状態"):
+			current.LCPStatus = value
+		case matchKey(key, "IPCP Status", "IPCP状態"):
+			current.IPCPStatus = value
+		default:
+			current.Fields[key] = value
+		}
+	}
+
+	flush()
+	return result, nil
+}
@@ -0,0 +1,133 @@
+// Package parsers はヤマハルーターのコマンドの出力 (CommandResultDetail.Output) を型付きの構造体に変換する
+//
+//	v, err := parsers.Parse("show environment", output)
+//	env := v.(*parsers.Environment)
+//
+// 英語と日本語どちらの表示 (console character) の出力にも対応する
+// 型付きのフィールドにならない行は各構造体の Fields に元の表記のまま残る
+package parsers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrNoParser は command に対応するパーサーが登録されていない場合に返される
+var ErrNoParser = errors.New("parsers: no parser registered for command")
+
+// Parser はコマンドの出力を解析する
+type Parser func(output []string) (any, error)
+
+type entry struct {
+	prefix string
+	parser Parser
+}
+
+var registry struct {
+	mu      sync.RWMutex
+	entries []entry
+}
+
+// Register は prefix で始まるコマンドのパーサーを登録する
+// コマンドは小文字にして連続する空白を1つにまとめてから語の単位で比較し、最も長く一致した prefix のパーサーが使われる
+// prefix の直後に続く番号 (lan1 の 1 など) は同じ語として扱う
+// 例えば "show status pp" は "show status pp 1" に一致し、"show status pptp" には一致しない
+// 同じ prefix を登録した場合は置き換える
+func Register(prefix string, parser Parser) {
+	prefix = normalizeCommand(prefix)
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	for i, e := range registry.entries {
+		if e.prefix == prefix {
+			registry.entries[i].parser = parser
+			return
+		}
+	}
+
+	registry.entries = append(registry.entries, entry{prefix: prefix, parser: parser})
+}
+
+// Lookup は command に対応するパーサーを返す
+func Lookup(command string) (Parser, bool) {
+	command = normalizeCommand(command)
+
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	var found *entry
+	for i, e := range registry.entries {
+		if !matchPrefix(command, e.prefix) {
+			continue
+		}
+
+		if found == nil || len(e.prefix) > len(found.prefix) {
+			found = &registry.entries[i]
+		}
+	}
+
+	if found == nil {
+		return nil, false
+	}
+
+	return found.parser, true
+}
+
+// Parse は command に対応するパーサーで output を解析する
+func Parse(command string, output []string) (any, error) {
+	parser, ok := Lookup(command)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNoParser, command)
+	}
+
+	v, err := parser(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse output of %q: %w", command, err)
+	}
+
+	return v, nil
+}
+
+// ParseAs は Parse の結果を T として返す
+func ParseAs[T any](command string, output []string) (T, error) {
+	var zero T
+
+	v, err := Parse(command, output)
+	if err != nil {
+		return zero, err
+	}
+
+	t, ok := v.(T)
+	if !ok {
+		return zero, fmt.Errorf("parsers: output of %q is %T, not %T", command, v, zero)
+	}
+
+	return t, nil
+}
+
+// matchPrefix は command が prefix の語で始まるかを返す
+func matchPrefix(command, prefix string) bool {
+	rest, ok := strings.CutPrefix(command, prefix)
+	if !ok {
+		return false
+	}
+
+	rest = strings.TrimLeft(rest, "0123456789")
+	return rest == "" || rest[0] == ' '
+}
+
+func normalizeCommand(command string) string {
+	return strings.ToLower(strings.Join(strings.Fields(command), " "))
+}
+
+func init() {
+	Register("show status lan", func(output []string) (any, error) { return ParseLANStatus(output) })
+	Register("show environment", func(output []string) (any, error) { return ParseEnvironment(output) })
+	Register("show ip route", func(output []string) (any, error) { return ParseIPRoute(output) })
+	Register("show ipsec sa", func(output []string) (any, error) { return ParseIPsecSA(output) })
+	Register("show nat descriptor address", func(output []string) (any, error) { return ParseNATDescriptorAddress(output) })
+	Register("show status pp", func(output []string) (any, error) { return ParsePPStatus(output) })
+}
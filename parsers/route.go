@@ -0,0 +1,66 @@
+package parsers
+
+import (
+	"strings"
+)
+
+// Route は show ip route の1行
+//
+//	Destination         Gateway          Interface       Kind  Additional Info.
+//	default             192.0.2.1             LAN2     static
+//	192.168.100.0/24    192.168.100.1         LAN1   implicit
+//	10.0.0.0/8          -                TUNNEL[1]     static
+type Route struct {
+	Destination string
+	// Gateway: ゲートウェイがない場合は空
+	Gateway   string
+	Interface string
+	Kind      string
+	// Additional: 付加情報 (metric など)
+	Additional string
+}
+
+// IPRoutes は show ip route の出力
+type IPRoutes struct {
+	Routes []Route
+}
+
+// ParseIPRoute は show ip route の出力を解析する
+func ParseIPRoute(output []string) (*IPRoutes, error) {
+	routes := &IPRoutes{}
+	for _, line := range nonEmptyLines(output) {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || isRouteHeader(fields[0]) {
+			continue
+		}
+
+		route := Route{
+			Destination: fields[0],
+			Gateway:     fields[1],
+			Interface:   fields[2],
+			Kind:        fields[3],
+			Additional:  strings.Join(fields[4:], " "),
+		}
+		if route.Gateway == "-" {
+			route.Gateway = ""
+		}
+
+		routes.Routes = append(routes.Routes, route)
+	}
+
+	return routes, nil
+}
+
+func isRouteHeader(field string) bool {
+	return strings.EqualFold(field, "Destination") || strings.HasPrefix(field, "宛先")
+}
+
+// Default はデフォルト経路を返す
+func (r *IPRoutes) Default() (Route, bool) {
+	for _, route := range r.Routes {
+		if route.Destination == "default" || route.Destination == "0.0.0.0/0" {
+			return route, true
+		}
+	}
+	return Route{}, false
+}
@@ -0,0 +1,143 @@
+package parsers
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var numberRegex = regexp.MustCompile(`-?\d+(?:\.\d+)?`)
+
+// splitField は "Key: Value" 形式の行を最初のコロンで分割する。全角のコロンにも対応する
+// 値に含まれる時刻やMACアドレスのコロンはそのまま残る
+func splitField(line string) (key, value string, ok bool) {
+	i := strings.IndexAny(line, ":：")
+	if i < 0 {
+		return "", "", false
+	}
+
+	key, value = line[:i], line[i:]
+	if strings.HasPrefix(value, "：") {
+		value = value[len("："):]
+	} else {
+		value = value[1:]
+	}
+
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", "", false
+	}
+
+	return key, strings.TrimSpace(value), true
+}
+
+// normalizeKey は比較のために項目名を小文字にして空白を取り除く
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.Join(strings.Fields(key), ""))
+}
+
+// matchKey は key が names のいずれかと一致するかを返す
+func matchKey(key string, names ...string) bool {
+	key = normalizeKey(key)
+	for _, name := range names {
+		if key == normalizeKey(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// numbers は s に含まれる数値を順に返す
+func numbers(s string) []float64 {
+	var values []float64
+	for _, m := range numberRegex.FindAllString(s, -1) {
+		v, err := strconv.ParseFloat(m, 64)
+		if err == nil {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// firstInt は s に含まれる最初の整数を返す
+func firstInt(s string) (int64, bool) {
+	values := numbers(s)
+	if len(values) == 0 {
+		return 0, false
+	}
+	return int64(values[0]), true
+}
+
+// packetCounter は "12345 packets (1234567 octets)" または "12345 パケット(1234567 オクテット)" を解析する
+func packetCounter(s string) Counter {
+	values := numbers(s)
+	var c Counter
+	if len(values) > 0 {
+		c.Packets = int64(values[0])
+	}
+	if len(values) > 1 {
+		c.Octets = int64(values[1])
+	}
+	return c
+}
+
+// Counter はパケット数とオクテット数
+type Counter struct {
+	Packets int64
+	Octets  int64
+}
+
+var (
+	daysRegex     = regexp.MustCompile(`(\d+)\s*(?:days?|日)`)
+	hmsRegex      = regexp.MustCompile(`(\d+):(\d{2}):(\d{2})`)
+	jaHourRegex   = regexp.MustCompile(`(\d+)\s*時間`)
+	jaMinuteRegex = regexp.MustCompile(`(\d+)\s*分`)
+	jaSecondRegex = regexp.MustCompile(`(\d+)\s*秒`)
+)
+
+// parseElapsed は "1days 02:03:04" や "1日 2時間 3分 4秒" 形式の経過時間を解析する
+func parseElapsed(s string) (time.Duration, bool) {
+	var d time.Duration
+	var found bool
+
+	if m := daysRegex.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		d += time.Duration(n) * 24 * time.Hour
+		found = true
+	}
+
+	if m := hmsRegex.FindStringSubmatch(s); m != nil {
+		h, _ := strconv.Atoi(m[1])
+		min, _ := strconv.Atoi(m[2])
+		sec, _ := strconv.Atoi(m[3])
+		d += time.Duration(h)*time.Hour + time.Duration(min)*time.Minute + time.Duration(sec)*time.Second
+		return d, true
+	}
+
+	for _, unit := range []struct {
+		re *regexp.Regexp
+		d  time.Duration
+	}{{jaHourRegex, time.Hour}, {jaMinuteRegex, time.Minute}, {jaSecondRegex, time.Second}} {
+		if m := unit.re.FindStringSubmatch(s); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			d += time.Duration(n) * unit.d
+			found = true
+		}
+	}
+
+	return d, found
+}
+
+// nonEmptyLines は前後の空白を取り除き、空行を除いた行を返す
+func nonEmptyLines(output []string) []string {
+	var lines []string
+	for _, line := range output {
+		for l := range strings.Lines(line) {
+			if l = strings.TrimSpace(l); l != "" {
+				lines = append(lines, l)
+			}
+		}
+	}
+	return lines
+}
@@ -0,0 +1,331 @@
+package yno
+
+import (
+	"errors"
+)
+
+// Condition は検索条件の1要素。RouterCondition か UserCondition として使う
+type Condition[W SearchRouterWhere | SearchUserWhere] struct {
+	where W
+	err   error
+}
+
+type RouterCondition = Condition[SearchRouterWhere]
+
+type UserCondition = Condition[SearchUserWhere]
+
+func (c Condition[W]) Where() (W, error) {
+	return c.where, c.err
+}
+
+func (c Condition[W]) String() string {
+	return formatWhere(any(c.where))
+}
+
+// And は全ての条件に一致する条件を返す
+func And[W SearchRouterWhere | SearchUserWhere](conds ...Condition[W]) Condition[W] {
+	if len(conds) == 1 {
+		return conds[0]
+	}
+
+	wheres, err := collectWheres(conds)
+	if err == nil && len(wheres) == 0 {
+		err = errors.New("and requires at least one condition")
+	}
+
+	var where W
+	switch w := any(&where).(type) {
+	case *SearchRouterWhere:
+		w.And = any(wheres).([]SearchRouterWhere)
+	case *SearchUserWhere:
+		w.And = any(wheres).([]SearchUserWhere)
+	}
+
+	return Condition[W]{where: where, err: err}
+}
+
+// Or はいずれかの条件に一致する条件を返す
+func Or[W SearchRouterWhere | SearchUserWhere](conds ...Condition[W]) Condition[W] {
+	if len(conds) == 1 {
+		return conds[0]
+	}
+
+	wheres, err := collectWheres(conds)
+	if err == nil && len(wheres) == 0 {
+		err = errors.New("or requires at least one condition")
+	}
+
+	var where W
+	switch w := any(&where).(type) {
+	case *SearchRouterWhere:
+		w.Or = any(wheres).([]SearchRouterWhere)
+	case *SearchUserWhere:
+		w.Or = any(wheres).([]SearchUserWhere)
+	}
+
+	return Condition[W]{where: where, err: err}
+}
+
+func collectWheres[W SearchRouterWhere | SearchUserWhere](conds []Condition[W]) ([]W, error) {
+	wheres := make([]W, 0, len(conds))
+	var errs []error
+	for _, cond := range conds {
+		if cond.err != nil {
+			errs = append(errs, cond.err)
+		}
+		wheres = append(wheres, cond.where)
+	}
+
+	return wheres, errors.Join(errs...)
+}
+
+func notEmpty(field string, values ...string) error {
+	if len(values) == 0 {
+		return ValidateErrorNotMatch{field, "at least one value"}
+	}
+
+	for _, v := range values {
+		if v == "" {
+			return ValidateErrorNotMatch{field, "not empty"}
+		}
+	}
+
+	return nil
+}
+
+// RouterTextField は $eq, $pm, $in で検索できるルーターの文字列フィールド
+type RouterTextField struct {
+	name string
+	eq   func(*SearchRouterEqualObject, string)
+	pm   func(*SearchRouterPartialMatchObject, string)
+	in   func(*SearchRouterInObject, []string)
+}
+
+func (f RouterTextField) Eq(value string) RouterCondition {
+	obj := &SearchRouterEqualObject{}
+	f.eq(obj, value)
+	return RouterCondition{where: SearchRouterWhere{Equal: obj}, err: notEmpty(f.name, value)}
+}
+
+func (f RouterTextField) Pm(value string) RouterCondition {
+	obj := &SearchRouterPartialMatchObject{}
+	f.pm(obj, value)
+	return RouterCondition{where: SearchRouterWhere{PartialMatch: obj}, err: notEmpty(f.name, value)}
+}
+
+func (f RouterTextField) In(values ...string) RouterCondition {
+	obj := &SearchRouterInObject{}
+	f.in(obj, values)
+	return RouterCondition{where: SearchRouterWhere{In: obj}, err: notEmpty(f.name, values...)}
+}
+
+// RouterStatusField は $eq, $in で検索できるルーターの DeviceStatus フィールド
+type RouterStatusField struct{}
+
+func (RouterStatusField) Eq(status DeviceStatus) RouterCondition {
+	return RouterCondition{
+		where: SearchRouterWhere{Equal: &SearchRouterEqualObject{DeviceStatus: status}},
+		err:   notEmpty("DeviceStatus", string(status)),
+	}
+}
+
+func (RouterStatusField) In(statuses ...DeviceStatus) RouterCondition {
+	values := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		values = append(values, string(status))
+	}
+
+	return RouterCondition{
+		where: SearchRouterWhere{In: &SearchRouterInObject{DeviceStatus: statuses}},
+		err:   notEmpty("DeviceStatus", values...),
+	}
+}
+
+// RouterArrayField は $inArray, $pmInArray で検索できるルーターの配列フィールド
+type RouterArrayField struct {
+	name string
+	set  func(*RouterAssignedObject, []string)
+}
+
+func (f RouterArrayField) InArray(values ...string) RouterCondition {
+	obj := &RouterAssignedObject{}
+	f.set(obj, values)
+	return RouterCondition{where: SearchRouterWhere{InArray: obj}, err: notEmpty(f.name, values...)}
+}
+
+func (f RouterArrayField) PmInArray(values ...string) RouterCondition {
+	obj := &RouterAssignedObject{}
+	f.set(obj, values)
+	return RouterCondition{where: SearchRouterWhere{PartialMatchInArray: obj}, err: notEmpty(f.name, values...)}
+}
+
+var (
+	SerialNumber = RouterTextField{
+		name: "SerialNumber",
+		eq:   func(o *SearchRouterEqualObject, v string) { o.SerialNumber = v },
+		pm:   func(o *SearchRouterPartialMatchObject, v string) { o.SerialNumber = v },
+		in:   func(o *SearchRouterInObject, v []string) { o.SerialNumber = v },
+	}
+	ModelName = RouterTextField{
+		name: "ModelName",
+		eq:   func(o *SearchRouterEqualObject, v string) { o.ModelName = v },
+		pm:   func(o *SearchRouterPartialMatchObject, v string) { o.ModelName = v },
+		in:   func(o *SearchRouterInObject, v []string) { o.ModelName = v },
+	}
+	FirmwareRevision = RouterTextField{
+		name: "FirmwareRevision",
+		eq:   func(o *SearchRouterEqualObject, v string) { o.FirmwareRevision = v },
+		pm:   func(o *SearchRouterPartialMatchObject, v string) { o.FirmwareRevision = v },
+		in:   func(o *SearchRouterInObject, v []string) { o.FirmwareRevision = v },
+	}
+	EndpointIpAddress = RouterTextField{
+		name: "EndpointIpAddress",
+		eq:   func(o *SearchRouterEqualObject, v string) { o.EndpointIpAddress = v },
+		pm:   func(o *SearchRouterPartialMatchObject, v string) { o.EndpointIpAddress = v },
+		in:   func(o *SearchRouterInObject, v []string) { o.EndpointIpAddress = v },
+	}
+	DeviceDescription = RouterTextField{
+		name: "DeviceDescription",
+		eq:   func(o *SearchRouterEqualObject, v string) { o.DeviceDescription = v },
+		pm:   func(o *SearchRouterPartialMatchObject, v string) { o.DeviceDescription = v },
+		in:   func(o *SearchRouterInObject, v []string) { o.DeviceDescription = v },
+	}
+	Status = RouterStatusField{}
+	Label  = RouterArrayField{
+		name: "AssignedLabels",
+		set:  func(o *RouterAssignedObject, v []string) { o.AssignedLabels = v },
+	}
+	AssignedUser = RouterArrayField{
+		name: "AssignedUsers",
+		set:  func(o *RouterAssignedObject, v []string) { o.AssignedUsers = v },
+	}
+)
+
+// UserTextField は $eq, $pm, $in で検索できるユーザーの文字列フィールド
+type UserTextField struct{}
+
+func (UserTextField) Eq(value string) UserCondition {
+	return UserCondition{
+		where: SearchUserWhere{Equal: &SearchUserEqualObject{AccountName: value}},
+		err:   notEmpty("AccountName", value),
+	}
+}
+
+func (UserTextField) Pm(value string) UserCondition {
+	return UserCondition{
+		where: SearchUserWhere{PartialMatch: &SearchUserPartialMatchObject{AccountName: value}},
+		err:   notEmpty("AccountName", value),
+	}
+}
+
+func (UserTextField) In(values ...string) UserCondition {
+	return UserCondition{
+		where: SearchUserWhere{In: &SearchUserInObject{AccountName: values}},
+		err:   notEmpty("AccountName", values...),
+	}
+}
+
+// UserStatusField は $eq で検索できるユーザーの AccountStatus フィールド
+type UserStatusField struct{}
+
+func (UserStatusField) Eq(status string) UserCondition {
+	return UserCondition{
+		where: SearchUserWhere{Equal: &SearchUserEqualObject{AccountStatus: status}},
+		err:   notEmpty("AccountStatus", status),
+	}
+}
+
+// UserArrayField は $inArray, $pmInArray で検索できるユーザーの配列フィールド
+type UserArrayField struct{}
+
+func (UserArrayField) InArray(values ...string) UserCondition {
+	return UserCondition{
+		where: SearchUserWhere{InArray: &SearchUserInArrayObject{EmailAddress: values}},
+		err:   notEmpty("EmailAddress", values...),
+	}
+}
+
+func (UserArrayField) PmInArray(values ...string) UserCondition {
+	return UserCondition{
+		where: SearchUserWhere{PartialMatchInArray: &SearchUserInArrayObject{EmailAddress: values}},
+		err:   notEmpty("EmailAddress", values...),
+	}
+}
+
+var (
+	AccountName   = UserTextField{}
+	AccountStatus = UserStatusField{}
+	EmailAddress  = UserArrayField{}
+)
+
+type RouterQueryBuilder struct {
+	conds []RouterCondition
+}
+
+// Routers はルーター検索の SearchRouterQuery を組み立てるビルダーを返す
+func Routers() *RouterQueryBuilder {
+	return &RouterQueryBuilder{}
+}
+
+// Where は条件を追加する。複数の条件は $and で結合される
+func (b *RouterQueryBuilder) Where(conds ...RouterCondition) *RouterQueryBuilder {
+	b.conds = append(b.conds, conds...)
+	return b
+}
+
+func (b *RouterQueryBuilder) Build() (*SearchRouterQuery, error) {
+	if len(b.conds) == 0 {
+		return &SearchRouterQuery{}, nil
+	}
+
+	where, err := And(b.conds...).Where()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchRouterQuery{Where: &where}, nil
+}
+
+func (b *RouterQueryBuilder) String() string {
+	if len(b.conds) == 0 {
+		return ""
+	}
+
+	return And(b.conds...).String()
+}
+
+type UserQueryBuilder struct {
+	conds []UserCondition
+}
+
+// Users はユーザー検索の SearchUserQuery を組み立てるビルダーを返す
+func Users() *UserQueryBuilder {
+	return &UserQueryBuilder{}
+}
+
+// Where は条件を追加する。複数の条件は $and で結合される
+func (b *UserQueryBuilder) Where(conds ...UserCondition) *UserQueryBuilder {
+	b.conds = append(b.conds, conds...)
+	return b
+}
+
+func (b *UserQueryBuilder) Build() (*SearchUserQuery, error) {
+	if len(b.conds) == 0 {
+		return &SearchUserQuery{}, nil
+	}
+
+	where, err := And(b.conds...).Where()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchUserQuery{Where: &where}, nil
+}
+
+func (b *UserQueryBuilder) String() string {
+	if len(b.conds) == 0 {
+		return ""
+	}
+
+	return And(b.conds...).String()
+}
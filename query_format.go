@@ -0,0 +1,263 @@
+package yno
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 検索条件を文字列にする際のフィールド名
+const (
+	fieldSerialNumber      = "serial"
+	fieldModelName         = "model"
+	fieldFirmwareRevision  = "firmware"
+	fieldEndpointIpAddress = "ip"
+	fieldDeviceDescription = "description"
+	fieldDeviceStatus      = "status"
+	fieldAssignedLabels    = "label"
+	fieldAssignedUsers     = "user"
+	fieldAccountName       = "name"
+	fieldAccountStatus     = "status"
+	fieldEmailAddress      = "email"
+)
+
+type formatNode struct {
+	terms []string
+	and   []formatNode
+	or    []formatNode
+}
+
+type formatKind int
+
+const (
+	formatKindEmpty formatKind = iota
+	formatKindSingle
+	formatKindAnd
+	formatKindOr
+)
+
+func formatWhere(where any) string {
+	var node formatNode
+	switch w := where.(type) {
+	case SearchRouterWhere:
+		node = routerFormatNode(w)
+	case SearchUserWhere:
+		node = userFormatNode(w)
+	}
+
+	s, _ := node.format()
+	return s
+}
+
+func (n formatNode) format() (string, formatKind) {
+	parts := append([]string(nil), n.terms...)
+	lastPartKind := formatKindSingle
+	for _, child := range n.and {
+		s, kind := child.format()
+		switch kind {
+		case formatKindEmpty:
+			continue
+		case formatKindOr:
+			s = "(" + s + ")"
+			kind = formatKindSingle
+		}
+		parts = append(parts, s)
+		lastPartKind = kind
+	}
+
+	var ors []string
+	var lastOr string
+	var lastOrKind formatKind
+	for _, child := range n.or {
+		s, kind := child.format()
+		if kind == formatKindEmpty {
+			continue
+		}
+		lastOr, lastOrKind = s, kind
+		if kind != formatKindSingle {
+			s = "(" + s + ")"
+		}
+		ors = append(ors, s)
+	}
+
+	switch {
+	case len(ors) == 1 && len(parts) == 0:
+		return lastOr, lastOrKind
+	case len(ors) == 1:
+		parts = append(parts, ors[0])
+		lastPartKind = formatKindSingle
+	case len(ors) > 1 && len(parts) == 0:
+		return strings.Join(ors, " or "), formatKindOr
+	case len(ors) > 1:
+		parts = append(parts, "("+strings.Join(ors, " or ")+")")
+		lastPartKind = formatKindSingle
+	}
+
+	switch len(parts) {
+	case 0:
+		return "", formatKindEmpty
+	case 1:
+		return parts[0], lastPartKind
+	default:
+		return strings.Join(parts, " and "), formatKindAnd
+	}
+}
+
+func formatTerm(field, op string, values ...string) string {
+	if len(values) == 1 && op != "in" {
+		return field + " " + op + " " + formatValue(values[0])
+	}
+
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, formatValue(v))
+	}
+
+	return field + " " + op + " [" + strings.Join(quoted, ", ") + "]"
+}
+
+var bareValueRegex = regexp.MustCompile(`^[A-Za-z0-9_.:/@-]+$`)
+
+func formatValue(v string) string {
+	if bareValueRegex.MatchString(v) && !isKeyword(v) {
+		return v
+	}
+
+	return strconv.Quote(v)
+}
+
+func isKeyword(v string) bool {
+	switch strings.ToLower(v) {
+	case "and", "or", "in":
+		return true
+	}
+
+	return false
+}
+
+func appendEq(terms []string, field, value string) []string {
+	if value == "" {
+		return terms
+	}
+	return append(terms, formatTerm(field, "=", value))
+}
+
+func appendPm(terms []string, field, value string) []string {
+	if value == "" {
+		return terms
+	}
+	return append(terms, formatTerm(field, "~", value))
+}
+
+func appendIn(terms []string, field string, values []string) []string {
+	if len(values) == 0 {
+		return terms
+	}
+	return append(terms, formatTerm(field, "in", values...))
+}
+
+func appendInArray(terms []string, field string, values []string) []string {
+	switch len(values) {
+	case 0:
+		return terms
+	case 1:
+		return append(terms, formatTerm(field, "=", values...))
+	default:
+		return append(terms, formatTerm(field, "in", values...))
+	}
+}
+
+func appendPmInArray(terms []string, field string, values []string) []string {
+	if len(values) == 0 {
+		return terms
+	}
+	return append(terms, formatTerm(field, "~", values...))
+}
+
+func routerFormatNode(w SearchRouterWhere) formatNode {
+	var terms []string
+	if eq := w.Equal; eq != nil {
+		terms = appendEq(terms, fieldSerialNumber, eq.SerialNumber)
+		terms = appendEq(terms, fieldModelName, eq.ModelName)
+		terms = appendEq(terms, fieldFirmwareRevision, eq.FirmwareRevision)
+		terms = appendEq(terms, fieldEndpointIpAddress, eq.EndpointIpAddress)
+		terms = appendEq(terms, fieldDeviceDescription, eq.DeviceDescription)
+		terms = appendEq(terms, fieldDeviceStatus, string(eq.DeviceStatus))
+	}
+
+	if pm := w.PartialMatch; pm != nil {
+		terms = appendPm(terms, fieldSerialNumber, pm.SerialNumber)
+		terms = appendPm(terms, fieldModelName, pm.ModelName)
+		terms = appendPm(terms, fieldFirmwareRevision, pm.FirmwareRevision)
+		terms = appendPm(terms, fieldEndpointIpAddress, pm.EndpointIpAddress)
+		terms = appendPm(terms, fieldDeviceDescription, pm.DeviceDescription)
+	}
+
+	if in := w.In; in != nil {
+		statuses := make([]string, 0, len(in.DeviceStatus))
+		for _, status := range in.DeviceStatus {
+			statuses = append(statuses, string(status))
+		}
+
+		terms = appendIn(terms, fieldSerialNumber, in.SerialNumber)
+		terms = appendIn(terms, fieldModelName, in.ModelName)
+		terms = appendIn(terms, fieldFirmwareRevision, in.FirmwareRevision)
+		terms = appendIn(terms, fieldEndpointIpAddress, in.EndpointIpAddress)
+		terms = appendIn(terms, fieldDeviceDescription, in.DeviceDescription)
+		terms = appendIn(terms, fieldDeviceStatus, statuses)
+	}
+
+	if in := w.InArray; in != nil {
+		terms = appendInArray(terms, fieldAssignedLabels, in.AssignedLabels)
+		terms = appendInArray(terms, fieldAssignedUsers, in.AssignedUsers)
+	}
+
+	if pm := w.PartialMatchInArray; pm != nil {
+		terms = appendPmInArray(terms, fieldAssignedLabels, pm.AssignedLabels)
+		terms = appendPmInArray(terms, fieldAssignedUsers, pm.AssignedUsers)
+	}
+
+	node := formatNode{terms: terms}
+	for _, and := range w.And {
+		node.and = append(node.and, routerFormatNode(and))
+	}
+	for _, or := range w.Or {
+		node.or = append(node.or, routerFormatNode(or))
+	}
+
+	return node
+}
+
+func userFormatNode(w SearchUserWhere) formatNode {
+	var terms []string
+	if eq := w.Equal; eq != nil {
+		terms = appendEq(terms, fieldAccountName, eq.AccountName)
+		terms = appendEq(terms, fieldAccountStatus, eq.AccountStatus)
+	}
+
+	if pm := w.PartialMatch; pm != nil {
+		terms = appendPm(terms, fieldAccountName, pm.AccountName)
+	}
+
+	if in := w.In; in != nil {
+		terms = appendIn(terms, fieldAccountName, in.AccountName)
+	}
+
+	if in := w.InArray; in != nil {
+		terms = appendInArray(terms, fieldEmailAddress, in.EmailAddress)
+	}
+
+	if pm := w.PartialMatchInArray; pm != nil {
+		terms = appendPmInArray(terms, fieldEmailAddress, pm.EmailAddress)
+	}
+
+	node := formatNode{terms: terms}
+	for _, and := range w.And {
+		node.and = append(node.and, userFormatNode(and))
+	}
+	for _, or := range w.Or {
+		node.or = append(node.or, userFormatNode(or))
+	}
+
+	return node
+}
@@ -0,0 +1,178 @@
+package yno
+
+import (
+	"slices"
+	"strings"
+)
+
+// Match はルーターが検索条件に一致するかを routers/_search と同じ規則で判定する
+//   - $eq, $pm, $in, $inArray, $pmInArray に指定された各フィールドは全て一致する必要がある
+//   - $in は値のいずれかに一致、$inArray は配列の要素のいずれかが値のいずれかに一致すればよい
+//   - $and は全ての条件、$or はいずれかの条件に一致する必要がある
+func (where SearchRouterWhere) Match(router RouterResponseRouter) bool {
+	if eq := where.Equal; eq != nil {
+		if !equalIfSet(eq.SerialNumber, router.SerialNumber) ||
+			!equalIfSet(eq.ModelName, router.ModelName) ||
+			!equalIfSet(eq.FirmwareRevision, router.FirmwareRevision) ||
+			!equalIfSet(eq.EndpointIpAddress, router.EndpointIPAddress) ||
+			!equalIfSet(eq.DeviceDescription, router.DeviceDescription) ||
+			!equalIfSet(string(eq.DeviceStatus), router.DeviceStatus) {
+			return false
+		}
+	}
+
+	if pm := where.PartialMatch; pm != nil {
+		if !containsIfSet(pm.SerialNumber, router.SerialNumber) ||
+			!containsIfSet(pm.ModelName, router.ModelName) ||
+			!containsIfSet(pm.FirmwareRevision, router.FirmwareRevision) ||
+			!containsIfSet(pm.EndpointIpAddress, router.EndpointIPAddress) ||
+			!containsIfSet(pm.DeviceDescription, router.DeviceDescription) {
+			return false
+		}
+	}
+
+	if in := where.In; in != nil {
+		var statuses []string
+		for _, status := range in.DeviceStatus {
+			statuses = append(statuses, string(status))
+		}
+
+		if !inIfSet(in.SerialNumber, router.SerialNumber) ||
+			!inIfSet(in.ModelName, router.ModelName) ||
+			!inIfSet(in.FirmwareRevision, router.FirmwareRevision) ||
+			!inIfSet(in.EndpointIpAddress, router.EndpointIPAddress) ||
+			!inIfSet(in.DeviceDescription, router.DeviceDescription) ||
+			!inIfSet(statuses, router.DeviceStatus) {
+			return false
+		}
+	}
+
+	if in := where.InArray; in != nil {
+		if !anyInIfSet(in.AssignedLabels, router.AssignedLabels) ||
+			!anyInIfSet(in.AssignedUsers, router.AssignedUsers) {
+			return false
+		}
+	}
+
+	if pm := where.PartialMatchInArray; pm != nil {
+		if !anyContainsIfSet(pm.AssignedLabels, router.AssignedLabels) ||
+			!anyContainsIfSet(pm.AssignedUsers, router.AssignedUsers) {
+			return false
+		}
+	}
+
+	for _, and := range where.And {
+		if !and.Match(router) {
+			return false
+		}
+	}
+
+	if len(where.Or) > 0 && !slices.ContainsFunc(where.Or, func(or SearchRouterWhere) bool {
+		return or.Match(router)
+	}) {
+		return false
+	}
+
+	return true
+}
+
+// Match はユーザーが検索条件に一致するかを users/_search と同じ規則で判定する
+// AccountStatus は API と同じく "Enabled" または "Disabled" と比較する
+func (where SearchUserWhere) Match(u UserResponseUser) bool {
+	var emailAddresses []string
+	for _, eafn := range u.EmailAddressesForNotification {
+		if eafn.EmailAddress != nil {
+			emailAddresses = append(emailAddresses, *eafn.EmailAddress)
+		}
+	}
+
+	if eq := where.Equal; eq != nil {
+		if !equalIfSet(eq.AccountName, u.AccountName) ||
+			!accountStatusIfSet(eq.AccountStatus, u.AccountStatus) {
+			return false
+		}
+	}
+
+	if pm := where.PartialMatch; pm != nil {
+		if !containsIfSet(pm.AccountName, u.AccountName) {
+			return false
+		}
+	}
+
+	if in := where.In; in != nil {
+		if !inIfSet(in.AccountName, u.AccountName) {
+			return false
+		}
+	}
+
+	if in := where.InArray; in != nil {
+		if !anyInIfSet(in.EmailAddress, emailAddresses) {
+			return false
+		}
+	}
+
+	if pm := where.PartialMatchInArray; pm != nil {
+		if !anyContainsIfSet(pm.EmailAddress, emailAddresses) {
+			return false
+		}
+	}
+
+	for _, and := range where.And {
+		if !and.Match(u) {
+			return false
+		}
+	}
+
+	if len(where.Or) > 0 && !slices.ContainsFunc(where.Or, func(or SearchUserWhere) bool {
+		return or.Match(u)
+	}) {
+		return false
+	}
+
+	return true
+}
+
+func accountStatusIfSet(want string, enabled bool) bool {
+	if want == "" {
+		return true
+	}
+
+	if enabled {
+		return want == "Enabled"
+	}
+	return want == "Disabled"
+}
+
+func equalIfSet(want, got string) bool {
+	return want == "" || want == got
+}
+
+func containsIfSet(want, got string) bool {
+	return want == "" || strings.Contains(got, want)
+}
+
+func inIfSet(want []string, got string) bool {
+	return len(want) == 0 || slices.Contains(want, got)
+}
+
+func anyInIfSet(want, got []string) bool {
+	if len(want) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(got, func(g string) bool {
+		return slices.Contains(want, g)
+	})
+}
+
+func anyContainsIfSet(want, got []string) bool {
+	if len(want) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(got, func(g string) bool {
+		return slices.ContainsFunc(want, func(w string) bool {
+			return strings.Contains(g, w)
+		})
+	})
+}
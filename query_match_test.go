@@ -0,0 +1,140 @@
+package yno
+
+import "testing"
+
+func TestSearchRouterWhereMatch(t *testing.T) {
+	router := RouterResponseRouter{
+		SerialNumber:      "S1",
+		ModelName:         "RTX1300",
+		FirmwareRevision:  "Rev.23.00.05",
+		EndpointIPAddress: "192.0.2.1",
+		DeviceDescription: "tokyo office",
+		DeviceStatus:      string(DeviceStatusOnline),
+		RouterAssignedObject: RouterAssignedObject{
+			AssignedLabels: []string{"tokyo-1", "core"},
+			AssignedUsers:  []string{"alice"},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		where SearchRouterWhere
+		want  bool
+	}{
+		{"empty", SearchRouterWhere{}, true},
+		{"$eq", SearchRouterWhere{Equal: &SearchRouterEqualObject{ModelName: "RTX1300", DeviceStatus: DeviceStatusOnline}}, true},
+		{"$eq all fields must match", SearchRouterWhere{Equal: &SearchRouterEqualObject{ModelName: "RTX1300", DeviceStatus: DeviceStatusOffline}}, false},
+		{"$eq is not a partial match", SearchRouterWhere{Equal: &SearchRouterEqualObject{ModelName: "RTX"}}, false},
+		{"$pm", SearchRouterWhere{PartialMatch: &SearchRouterPartialMatchObject{DeviceDescription: "tokyo"}}, true},
+		{"$pm not contained", SearchRouterWhere{PartialMatch: &SearchRouterPartialMatchObject{DeviceDescription: "osaka"}}, false},
+		{"$in", SearchRouterWhere{In: &SearchRouterInObject{SerialNumber: []string{"S2", "S1"}}}, true},
+		{"$in status", SearchRouterWhere{In: &SearchRouterInObject{DeviceStatus: []DeviceStatus{DeviceStatusOffline, DeviceStatusError}}}, false},
+		{"$inArray", SearchRouterWhere{InArray: &RouterAssignedObject{AssignedLabels: []string{"core", "edge"}}}, true},
+		{"$inArray is not a partial match", SearchRouterWhere{InArray: &RouterAssignedObject{AssignedLabels: []string{"tokyo"}}}, false},
+		{"$inArray users", SearchRouterWhere{InArray: &RouterAssignedObject{AssignedUsers: []string{"bob"}}}, false},
+		{"$pmInArray", SearchRouterWhere{PartialMatchInArray: &RouterAssignedObject{AssignedLabels: []string{"tokyo"}}}, true},
+		{"$pmInArray not contained", SearchRouterWhere{PartialMatchInArray: &RouterAssignedObject{AssignedUsers: []string{"bo"}}}, false},
+		{"$and", SearchRouterWhere{And: []SearchRouterWhere{
+			{Equal: &SearchRouterEqualObject{ModelName: "RTX1300"}},
+			{InArray: &RouterAssignedObject{AssignedUsers: []string{"alice"}}},
+		}}, true},
+		{"$and one fails", SearchRouterWhere{And: []SearchRouterWhere{
+			{Equal: &SearchRouterEqualObject{ModelName: "RTX1300"}},
+			{InArray: &RouterAssignedObject{AssignedUsers: []string{"bob"}}},
+		}}, false},
+		{"$or", SearchRouterWhere{Or: []SearchRouterWhere{
+			{Equal: &SearchRouterEqualObject{ModelName: "RTX830"}},
+			{PartialMatch: &SearchRouterPartialMatchObject{SerialNumber: "S"}},
+		}}, true},
+		{"$or none match", SearchRouterWhere{Or: []SearchRouterWhere{
+			{Equal: &SearchRouterEqualObject{ModelName: "RTX830"}},
+			{Equal: &SearchRouterEqualObject{SerialNumber: "S2"}},
+		}}, false},
+		{"fields and $or combined", SearchRouterWhere{
+			Equal: &SearchRouterEqualObject{ModelName: "RTX830"},
+			Or:    []SearchRouterWhere{{Equal: &SearchRouterEqualObject{SerialNumber: "S1"}}},
+		}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.where.Match(router); got != tt.want {
+			t.Errorf("%s: Match() = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSearchUserWhereMatch(t *testing.T) {
+	u := UserResponseUser{
+		AccountName:   "alice",
+		AccountStatus: true,
+		EmailAddressesForNotification: []EmailAddressesForNotification{
+			{EmailAddress: Ptr("alice@example.com")},
+			{EmailAddress: Ptr("noc@example.net")},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		where SearchUserWhere
+		want  bool
+	}{
+		{"empty", SearchUserWhere{}, true},
+		{"$eq", SearchUserWhere{Equal: &SearchUserEqualObject{AccountName: "alice", AccountStatus: "Enabled"}}, true},
+		{"$eq account name", SearchUserWhere{Equal: &SearchUserEqualObject{AccountName: "ali"}}, false},
+		{"$eq account status", SearchUserWhere{Equal: &SearchUserEqualObject{AccountStatus: "Disabled"}}, false},
+		{"$pm", SearchUserWhere{PartialMatch: &SearchUserPartialMatchObject{AccountName: "lic"}}, true},
+		{"$pm not contained", SearchUserWhere{PartialMatch: &SearchUserPartialMatchObject{AccountName: "bob"}}, false},
+		{"$in", SearchUserWhere{In: &SearchUserInObject{AccountName: []string{"bob", "alice"}}}, true},
+		{"$in not included", SearchUserWhere{In: &SearchUserInObject{AccountName: []string{"bob"}}}, false},
+		{"$inArray", SearchUserWhere{InArray: &SearchUserInArrayObject{EmailAddress: []string{"noc@example.net"}}}, true},
+		{"$inArray is not a partial match", SearchUserWhere{InArray: &SearchUserInArrayObject{EmailAddress: []string{"example.net"}}}, false},
+		{"$pmInArray", SearchUserWhere{PartialMatchInArray: &SearchUserInArrayObject{EmailAddress: []string{"example.net"}}}, true},
+		{"$pmInArray not contained", SearchUserWhere{PartialMatchInArray: &SearchUserInArrayObject{EmailAddress: []string{"example.org"}}}, false},
+		{"$and", SearchUserWhere{And: []SearchUserWhere{
+			{Equal: &SearchUserEqualObject{AccountStatus: "Enabled"}},
+			{PartialMatch: &SearchUserPartialMatchObject{AccountName: "al"}},
+		}}, true},
+		{"$and one fails", SearchUserWhere{And: []SearchUserWhere{
+			{Equal: &SearchUserEqualObject{AccountStatus: "Enabled"}},
+			{PartialMatch: &SearchUserPartialMatchObject{AccountName: "bo"}},
+		}}, false},
+		{"$or", SearchUserWhere{Or: []SearchUserWhere{
+			{Equal: &SearchUserEqualObject{AccountName: "bob"}},
+			{InArray: &SearchUserInArrayObject{EmailAddress: []string{"alice@example.com"}}},
+		}}, true},
+		{"$or none match", SearchUserWhere{Or: []SearchUserWhere{
+			{Equal: &SearchUserEqualObject{AccountName: "bob"}},
+			{Equal: &SearchUserEqualObject{AccountStatus: "Disabled"}},
+		}}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.where.Match(u); got != tt.want {
+			t.Errorf("%s: Match() = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSearchUserWhereMatchAccountStatus(t *testing.T) {
+	tests := []struct {
+		status  string
+		enabled bool
+		want    bool
+	}{
+		{"Enabled", true, true},
+		{"Disabled", true, false},
+		{"Disabled", false, true},
+		{"Enabled", false, false},
+		{"enabled", true, false},
+		{"true", true, false},
+		{"false", false, false},
+	}
+
+	for _, tt := range tests {
+		where := SearchUserWhere{Equal: &SearchUserEqualObject{AccountStatus: tt.status}}
+		u := UserResponseUser{AccountName: "alice", AccountStatus: tt.enabled}
+		if got := where.Match(u); got != tt.want {
+			t.Errorf("Match(AccountStatus=%q) with enabled=%t = %t, want %t", tt.status, tt.enabled, got, tt.want)
+		}
+	}
+}
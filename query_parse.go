@@ -0,0 +1,471 @@
+package yno
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseRouterQuery はテキスト形式の検索条件を SearchRouterQuery に変換する
+//
+//	model = RTX1300 and (status in [Offline, Error] or label ~ tokyo)
+//
+// 演算子は = ($eq), ~ ($pm), in ($in) で、label と user に対しては
+// = と in が $inArray、~ が $pmInArray になる
+// status の値は DeviceStatus のいずれか (大文字小文字は区別しない) で、それ以外は QueryParseError を返す
+func ParseRouterQuery(s string) (*SearchRouterQuery, error) {
+	cond, empty, err := parseQuery(s, routerTerm)
+	if err != nil {
+		return nil, err
+	}
+
+	if empty {
+		return &SearchRouterQuery{}, nil
+	}
+
+	return &SearchRouterQuery{Where: &cond.where}, nil
+}
+
+// ParseUserQuery はテキスト形式の検索条件を SearchUserQuery に変換する
+//
+//	name ~ alice or email = alice@example.com
+func ParseUserQuery(s string) (*SearchUserQuery, error) {
+	cond, empty, err := parseQuery(s, userTerm)
+	if err != nil {
+		return nil, err
+	}
+
+	if empty {
+		return &SearchUserQuery{}, nil
+	}
+
+	return &SearchUserQuery{Where: &cond.where}, nil
+}
+
+// FormatRouterQuery は SearchRouterQuery を ParseRouterQuery で読めるテキスト形式にする
+func FormatRouterQuery(q *SearchRouterQuery) string {
+	if q == nil || q.Where == nil {
+		return ""
+	}
+
+	return formatWhere(*q.Where)
+}
+
+// FormatUserQuery は SearchUserQuery を ParseUserQuery で読めるテキスト形式にする
+func FormatUserQuery(q *SearchUserQuery) string {
+	if q == nil || q.Where == nil {
+		return ""
+	}
+
+	return formatWhere(*q.Where)
+}
+
+func routerTerm(field, op string, values []string) (RouterCondition, error) {
+	switch strings.ToLower(field) {
+	case fieldSerialNumber, "serialnumber":
+		return textTerm(SerialNumber, op, values)
+	case fieldModelName, "modelname":
+		return textTerm(ModelName, op, values)
+	case fieldFirmwareRevision, "firmwarerevision":
+		return textTerm(FirmwareRevision, op, values)
+	case fieldEndpointIpAddress, "endpointipaddress":
+		return textTerm(EndpointIpAddress, op, values)
+	case fieldDeviceDescription, "devicedescription":
+		return textTerm(DeviceDescription, op, values)
+	case fieldDeviceStatus, "devicestatus":
+		statuses := make([]DeviceStatus, 0, len(values))
+		for i, v := range values {
+			status, err := parseDeviceStatus(v)
+			if err != nil {
+				return RouterCondition{}, &queryValueError{index: i, err: err}
+			}
+			statuses = append(statuses, status)
+		}
+
+		switch op {
+		case "=":
+			return Status.Eq(statuses[0]), nil
+		case "in":
+			return Status.In(statuses...), nil
+		}
+	case fieldAssignedLabels, "assignedlabels":
+		return arrayTerm(Label, op, values)
+	case fieldAssignedUsers, "assignedusers":
+		return arrayTerm(AssignedUser, op, values)
+	default:
+		return RouterCondition{}, fmt.Errorf("unknown field %q", field)
+	}
+
+	return RouterCondition{}, fmt.Errorf("operator %q is not supported for %s", op, field)
+}
+
+// deviceStatuses は status に指定できる値
+var deviceStatuses = []DeviceStatus{
+	DeviceStatusOnline,
+	DeviceStatusOffline,
+	DeviceStatusCommunicating,
+	DeviceStatusProcessing,
+	DeviceStatusError,
+}
+
+// parseDeviceStatus は大文字小文字を区別せずに v を DeviceStatus に変換する
+func parseDeviceStatus(v string) (DeviceStatus, error) {
+	for _, status := range deviceStatuses {
+		if strings.EqualFold(v, string(status)) {
+			return status, nil
+		}
+	}
+
+	names := make([]string, 0, len(deviceStatuses))
+	for _, status := range deviceStatuses {
+		names = append(names, string(status))
+	}
+	return "", fmt.Errorf("invalid status %q (expected one of %s)", v, strings.Join(names, ", "))
+}
+
+// queryValueError は term が values[index] の値を受け付けない場合に返す
+// parsePrimary はその値の位置を QueryParseError の Pos にする
+type queryValueError struct {
+	index int
+	err   error
+}
+
+func (e *queryValueError) Error() string {
+	return e.err.Error()
+}
+
+func textTerm(f RouterTextField, op string, values []string) (RouterCondition, error) {
+	switch op {
+	case "=":
+		return f.Eq(values[0]), nil
+	case "~":
+		return f.Pm(values[0]), nil
+	default:
+		return f.In(values...), nil
+	}
+}
+
+func arrayTerm(f RouterArrayField, op string, values []string) (RouterCondition, error) {
+	if op == "~" {
+		return f.PmInArray(values...), nil
+	}
+
+	return f.InArray(values...), nil
+}
+
+func userTerm(field, op string, values []string) (UserCondition, error) {
+	switch strings.ToLower(field) {
+	case fieldAccountName, "accountname":
+		switch op {
+		case "=":
+			return AccountName.Eq(values[0]), nil
+		case "~":
+			return AccountName.Pm(values[0]), nil
+		default:
+			return AccountName.In(values...), nil
+		}
+	case fieldAccountStatus, "accountstatus":
+		if op == "=" {
+			return AccountStatus.Eq(values[0]), nil
+		}
+	case fieldEmailAddress, "emailaddress":
+		if op == "~" {
+			return EmailAddress.PmInArray(values...), nil
+		}
+		return EmailAddress.InArray(values...), nil
+	default:
+		return UserCondition{}, fmt.Errorf("unknown field %q", field)
+	}
+
+	return UserCondition{}, fmt.Errorf("operator %q is not supported for %s", op, field)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+	tokenComma
+	tokenEq
+	tokenTilde
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) is(keyword string) bool {
+	return t.kind == tokenIdent && strings.EqualFold(t.value, keyword)
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of input"
+	case tokenString:
+		return strconv.Quote(t.value)
+	default:
+		return fmt.Sprintf("%q", t.value)
+	}
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("()[],=~", r):
+			kind := map[rune]tokenKind{
+				'(': tokenLParen, ')': tokenRParen,
+				'[': tokenLBracket, ']': tokenRBracket,
+				',': tokenComma, '=': tokenEq, '~': tokenTilde,
+			}[r]
+			tokens = append(tokens, token{kind: kind, value: string(r), pos: i})
+			i++
+		case r == '"':
+			start := i
+			for i++; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(runes) {
+				return nil, &QueryParseError{Pos: start, Msg: "unterminated string"}
+			}
+			i++
+
+			value, err := strconv.Unquote(string(runes[start:i]))
+			if err != nil {
+				return nil, &QueryParseError{Pos: start, Msg: "invalid string: " + err.Error()}
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, pos: start})
+		case isIdentRune(r):
+			start := i
+			for i < len(runes) && isIdentRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, value: string(runes[start:i]), pos: start})
+		default:
+			return nil, &QueryParseError{Pos: i, Msg: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.:/@-", r)
+}
+
+type queryParser[W SearchRouterWhere | SearchUserWhere] struct {
+	tokens []token
+	pos    int
+	term   func(field, op string, values []string) (Condition[W], error)
+}
+
+func parseQuery[W SearchRouterWhere | SearchUserWhere](s string, term func(field, op string, values []string) (Condition[W], error)) (Condition[W], bool, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return Condition[W]{}, false, err
+	}
+
+	if tokens[0].kind == tokenEOF {
+		return Condition[W]{}, true, nil
+	}
+
+	p := &queryParser[W]{tokens: tokens, term: term}
+	cond, err := p.parseOr()
+	if err != nil {
+		return Condition[W]{}, false, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return Condition[W]{}, false, &QueryParseError{Pos: t.pos, Msg: "unexpected " + t.describe()}
+	}
+
+	if cond.err != nil {
+		return Condition[W]{}, false, cond.err
+	}
+
+	return cond, false, nil
+}
+
+func (p *queryParser[W]) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser[W]) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *queryParser[W]) expect(kind tokenKind, what string) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, &QueryParseError{Pos: t.pos, Msg: fmt.Sprintf("expected %s, got %s", what, t.describe())}
+	}
+	return t, nil
+}
+
+func (p *queryParser[W]) parseOr() (Condition[W], error) {
+	conds := []Condition[W]{}
+	for {
+		cond, err := p.parseAnd()
+		if err != nil {
+			return cond, err
+		}
+		conds = append(conds, cond)
+
+		if !p.peek().is("or") {
+			return Or(conds...), nil
+		}
+		p.next()
+	}
+}
+
+func (p *queryParser[W]) parseAnd() (Condition[W], error) {
+	conds := []Condition[W]{}
+	for {
+		cond, err := p.parsePrimary()
+		if err != nil {
+			return cond, err
+		}
+		conds = append(conds, cond)
+
+		if !p.peek().is("and") {
+			return And(conds...), nil
+		}
+		p.next()
+	}
+}
+
+func (p *queryParser[W]) parsePrimary() (Condition[W], error) {
+	if p.peek().kind == tokenLParen {
+		p.next()
+		cond, err := p.parseOr()
+		if err != nil {
+			return cond, err
+		}
+
+		if _, err := p.expect(tokenRParen, `")"`); err != nil {
+			return cond, err
+		}
+		return cond, nil
+	}
+
+	field, err := p.expect(tokenIdent, "field name")
+	if err != nil {
+		return Condition[W]{}, err
+	}
+
+	opToken := p.next()
+	var op string
+	switch {
+	case opToken.kind == tokenEq:
+		op = "="
+	case opToken.kind == tokenTilde:
+		op = "~"
+	case opToken.is("in"):
+		op = "in"
+	default:
+		return Condition[W]{}, &QueryParseError{Pos: opToken.pos, Msg: fmt.Sprintf("expected operator (=, ~, in), got %s", opToken.describe())}
+	}
+
+	valuePos := p.peek().pos
+	values, positions, isList, err := p.parseValues()
+	if err != nil {
+		return Condition[W]{}, err
+	}
+
+	if op == "in" && !isList {
+		return Condition[W]{}, &QueryParseError{Pos: valuePos, Msg: "in requires a list such as [a, b]"}
+	}
+
+	cond, err := p.term(field.value, op, values)
+	var valueErr *queryValueError
+	if errors.As(err, &valueErr) {
+		return Condition[W]{}, &QueryParseError{Pos: positions[valueErr.index], Msg: valueErr.Error()}
+	}
+	if err != nil {
+		return Condition[W]{}, &QueryParseError{Pos: field.pos, Msg: err.Error()}
+	}
+
+	if cond.err != nil {
+		return Condition[W]{}, &QueryParseError{Pos: valuePos, Msg: cond.err.Error()}
+	}
+
+	// 単一の値しか取らない演算子にリストが渡された場合
+	if isList && op != "in" && !isArrayCondition(cond) {
+		return Condition[W]{}, &QueryParseError{Pos: valuePos, Msg: fmt.Sprintf("%s %s takes a single value", field.value, op)}
+	}
+
+	return cond, nil
+}
+
+// parseValues は値または値のリストと、それぞれの値の位置を返す
+func (p *queryParser[W]) parseValues() ([]string, []int, bool, error) {
+	if p.peek().kind != tokenLBracket {
+		pos := p.peek().pos
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, nil, false, err
+		}
+		return []string{v}, []int{pos}, false, nil
+	}
+
+	p.next()
+	var values []string
+	var positions []int
+	for {
+		pos := p.peek().pos
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, nil, true, err
+		}
+		values = append(values, v)
+		positions = append(positions, pos)
+
+		t := p.next()
+		switch t.kind {
+		case tokenComma:
+			continue
+		case tokenRBracket:
+			return values, positions, true, nil
+		default:
+			return nil, nil, true, &QueryParseError{Pos: t.pos, Msg: fmt.Sprintf(`expected "," or "]", got %s`, t.describe())}
+		}
+	}
+}
+
+func (p *queryParser[W]) parseValue() (string, error) {
+	t := p.next()
+	if t.kind != tokenIdent && t.kind != tokenString {
+		return "", &QueryParseError{Pos: t.pos, Msg: "expected value, got " + t.describe()}
+	}
+	return t.value, nil
+}
+
+func isArrayCondition[W SearchRouterWhere | SearchUserWhere](cond Condition[W]) bool {
+	switch w := any(cond.where).(type) {
+	case SearchRouterWhere:
+		return w.InArray != nil || w.PartialMatchInArray != nil
+	case SearchUserWhere:
+		return w.InArray != nil || w.PartialMatchInArray != nil
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package yno
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseRouterQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  RouterCondition
+	}{
+		{"model = RTX1300", ModelName.Eq("RTX1300")},
+		{"status in [offline, ERROR]", Status.In(DeviceStatusOffline, DeviceStatusError)},
+		{"label ~ tokyo", Label.PmInArray("tokyo")},
+		{
+			`model = RTX1300 and (status = Online or description ~ "tokyo office")`,
+			And(ModelName.Eq("RTX1300"), Or(Status.Eq(DeviceStatusOnline), DeviceDescription.Pm("tokyo office"))),
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRouterQuery(tt.query)
+		if err != nil {
+			t.Errorf("ParseRouterQuery(%q) error = %v", tt.query, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(*got.Where, tt.want.where) {
+			t.Errorf("ParseRouterQuery(%q) = %+v, want %+v", tt.query, *got.Where, tt.want.where)
+		}
+	}
+}
+
+func TestParseQueryErrorPosition(t *testing.T) {
+	tests := []struct {
+		query string
+		user  bool
+		pos   int
+	}{
+		{"status = Broken", false, 9},
+		{"model = RTX1300 and status in [Online, Broken]", false, 39},
+		{`status in [Online, "Off line"]`, false, 19},
+		{"color = red", false, 0},
+		{"model > RTX1300", false, 6},
+		{"model in RTX1300", false, 9},
+		{"model = [RTX1300, RTX830]", false, 8},
+		{"(model = RTX1300", false, 16},
+		{"model = RTX1300 or", false, 18},
+		{"model = RTX1300 serial = S1", false, 16},
+		{`description = "tokyo`, false, 14},
+		{"model = RTX1300 & serial = S1", false, 16},
+		{"status ~ Online", false, 0},
+		{"email = alice@example.com and status ~ Enabled", true, 30},
+		{"name = alice or", true, 15},
+	}
+
+	for _, tt := range tests {
+		var err error
+		if tt.user {
+			_, err = ParseUserQuery(tt.query)
+		} else {
+			_, err = ParseRouterQuery(tt.query)
+		}
+
+		var parseErr *QueryParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("Parse(%q) error = %v, want *QueryParseError", tt.query, err)
+			continue
+		}
+		if parseErr.Pos != tt.pos {
+			t.Errorf("Parse(%q) error at %d (%s), want %d", tt.query, parseErr.Pos, parseErr.Msg, tt.pos)
+		}
+	}
+}
+
+func TestFormatRouterQueryRoundTrip(t *testing.T) {
+	tests := []RouterCondition{
+		ModelName.Eq("RTX1300"),
+		SerialNumber.In("S1", "S2"),
+		DeviceDescription.Pm("tokyo office"),
+		FirmwareRevision.Eq(`Rev."23"`),
+		Status.In(DeviceStatusOffline, DeviceStatusError),
+		Label.InArray("tokyo", "osaka"),
+		AssignedUser.PmInArray("ali"),
+		And(ModelName.Eq("RTX1300"), Label.InArray("core")),
+		Or(Status.Eq(DeviceStatusOffline), Label.PmInArray("tokyo")),
+		And(ModelName.Eq("RTX1300"), Or(Status.In(DeviceStatusOffline, DeviceStatusError), Label.PmInArray("tokyo"))),
+		Or(And(ModelName.Eq("RTX830"), SerialNumber.Pm("S")), And(ModelName.Eq("RTX1300"), EndpointIpAddress.Eq("192.0.2.1"))),
+		And(Or(ModelName.Eq("RTX830"), ModelName.Eq("RTX1300")), Or(Label.InArray("a"), AssignedUser.InArray("b"))),
+	}
+
+	for _, cond := range tests {
+		text := FormatRouterQuery(&SearchRouterQuery{Where: &cond.where})
+
+		got, err := ParseRouterQuery(text)
+		if err != nil {
+			t.Errorf("ParseRouterQuery(%q) error = %v", text, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(*got.Where, cond.where) {
+			t.Errorf("ParseRouterQuery(%q) = %+v, want %+v", text, *got.Where, cond.where)
+		}
+	}
+}
+
+func TestFormatUserQueryRoundTrip(t *testing.T) {
+	tests := []UserCondition{
+		AccountName.Eq("alice"),
+		AccountName.Pm("ali"),
+		AccountName.In("alice", "bob"),
+		AccountStatus.Eq("Disabled"),
+		EmailAddress.InArray("alice@example.com"),
+		EmailAddress.PmInArray("example.com", "example.net"),
+		And(AccountStatus.Eq("Enabled"), EmailAddress.PmInArray("example.com")),
+		Or(AccountName.Pm("ali"), And(AccountName.In("bob", "carol"), AccountStatus.Eq("Disabled"))),
+	}
+
+	for _, cond := range tests {
+		text := FormatUserQuery(&SearchUserQuery{Where: &cond.where})
+
+		got, err := ParseUserQuery(text)
+		if err != nil {
+			t.Errorf("ParseUserQuery(%q) error = %v", text, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(*got.Where, cond.where) {
+			t.Errorf("ParseUserQuery(%q) = %+v, want %+v", text, *got.Where, cond.where)
+		}
+	}
+}
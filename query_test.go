@@ -0,0 +1,126 @@
+package yno
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestRouterQueryBuilderBuild(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *RouterQueryBuilder
+		want    string
+	}{
+		{"empty", Routers(), `{}`},
+		{
+			"single condition",
+			Routers().Where(ModelName.Eq("RTX1300")),
+			`{"Where":{"$eq":{"ModelName":"RTX1300"}}}`,
+		},
+		{
+			"conditions are joined with $and",
+			Routers().Where(ModelName.Eq("RTX1300")).Where(Label.InArray("tokyo", "osaka")),
+			`{"Where":{"$and":[{"$eq":{"ModelName":"RTX1300"}},{"$inArray":{"AssignedLabels":["tokyo","osaka"]}}]}}`,
+		},
+		{
+			"nested $or",
+			Routers().Where(Status.In(DeviceStatusOffline, DeviceStatusError), Or(SerialNumber.Pm("S1"), AssignedUser.PmInArray("ali"))),
+			`{"Where":{"$and":[{"$in":{"DeviceStatus":["Offline","Error"]}},{"$or":[{"$pm":{"SerialNumber":"S1"}},{"$pmInArray":{"AssignedUsers":["ali"]}}]}]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		q, err := tt.builder.Build()
+		if err != nil {
+			t.Errorf("%s: Build() error = %v", tt.name, err)
+			continue
+		}
+
+		got, err := json.Marshal(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s: Build() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUserQueryBuilderBuild(t *testing.T) {
+	q, err := Users().Where(AccountStatus.Eq("Enabled"), Or(AccountName.In("alice", "bob"), EmailAddress.PmInArray("example.com"))).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"Where":{"$and":[{"$eq":{"AccountStatus":"Enabled"}},{"$or":[{"$in":{"AccountName":["alice","bob"]}},{"$pmInArray":{"EmailAddress":["example.com"]}}]}]}}`
+	if string(got) != want {
+		t.Errorf("Build() = %s, want %s", got, want)
+	}
+}
+
+func TestQueryBuilderErrors(t *testing.T) {
+	// 入れ子の And, Or の中のエラーも全てまとめて返す
+	_, err := Routers().Where(
+		ModelName.Eq(""),
+		Or(SerialNumber.Eq("S1"), And(Label.InArray(), DeviceDescription.Pm("tokyo"))),
+	).Build()
+
+	want := []ValidateErrorNotMatch{
+		{"ModelName", "not empty"},
+		{"AssignedLabels", "at least one value"},
+	}
+	for _, w := range want {
+		if !errors.Is(err, w) {
+			t.Errorf("Build() error = %v, want %v", err, w)
+		}
+	}
+
+	if _, err := Users().Where(Or(AccountName.Eq("alice"), EmailAddress.InArray(""))).Build(); !errors.Is(err, ValidateErrorNotMatch{"EmailAddress", "not empty"}) {
+		t.Errorf("Build() error = %v, want EmailAddress error", err)
+	}
+
+	if _, err := And[SearchRouterWhere]().Where(); err == nil {
+		t.Error("And() with no conditions succeeded")
+	}
+	if _, err := Or[SearchUserWhere]().Where(); err == nil {
+		t.Error("Or() with no conditions succeeded")
+	}
+}
+
+func TestQueryBuilderString(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"empty router query", Routers().String(), ""},
+		{"empty user query", Users().String(), ""},
+		{
+			"router query",
+			Routers().Where(ModelName.Eq("RTX1300"), Or(Status.In(DeviceStatusOffline, DeviceStatusError), Label.PmInArray("tokyo"))).String(),
+			"model = RTX1300 and (status in [Offline, Error] or label ~ tokyo)",
+		},
+		{
+			"values are quoted when needed",
+			Routers().Where(DeviceDescription.Eq("tokyo office")).String(),
+			`description = "tokyo office"`,
+		},
+		{
+			"user query",
+			Users().Where(Or(AccountName.Pm("alice"), EmailAddress.InArray("alice@example.com"))).String(),
+			"name ~ alice or email = alice@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: String() = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
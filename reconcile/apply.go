@@ -0,0 +1,156 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	yno "github.com/murasame29/yno-sdk"
+)
+
+const defaultConcurrency = 4
+
+// ErrSkipped は依存する変更が失敗したために実行しなかった変更に対して返される
+var ErrSkipped = errors.New("reconcile: skipped because a dependency failed")
+
+type ApplyOptions struct {
+	// Concurrency: 同時に実行する変更の数。0 の場合は 4
+	Concurrency int
+}
+
+// ActionResult は1つの変更の実行結果
+type ActionResult struct {
+	Action Action
+	// Err: 変更に失敗した場合のエラー
+	Err error
+	// GeneratedPassword: ユーザーの作成時にYNOが自動生成したパスワード
+	GeneratedPassword string
+}
+
+// Result は Apply の結果。Results は Plan.Actions と同じ順に並ぶ
+type Result struct {
+	Results []ActionResult
+}
+
+// Failed は失敗またはスキップした変更の結果を返す
+func (r *Result) Failed() []ActionResult {
+	var failed []ActionResult
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Apply は plan の変更を実行する
+// ユーザーの作成・更新、ルーターの更新、ユーザーの削除の順に段階的に実行し、各段階の中では Concurrency 件ずつ並行に実行する
+// 一部の変更が失敗しても残りの変更は続ける。ただし作成に失敗したユーザーを割り当てるルーターの更新は ErrSkipped とする
+// 失敗した変更がある場合は全てのエラーをまとめて返す
+func Apply(ctx context.Context, c *yno.YNOClient, plan *Plan, options *ApplyOptions, opts ...yno.OptionFunc) (*Result, error) {
+	if options == nil {
+		options = &ApplyOptions{}
+	}
+
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	result := &Result{Results: make([]ActionResult, len(plan.Actions))}
+	for i, action := range plan.Actions {
+		result.Results[i].Action = action
+	}
+
+	failedUsers := map[string]bool{}
+	for _, phase := range []func(Action) bool{isUserUpsert, isRouterUpdate, isUserDelete} {
+		var indexes []int
+		for i, action := range plan.Actions {
+			if !phase(action) {
+				continue
+			}
+
+			if dep := failedDependency(action, failedUsers); dep != "" {
+				result.Results[i].Err = fmt.Errorf("%w: %s", ErrSkipped, dep)
+				continue
+			}
+
+			indexes = append(indexes, i)
+		}
+
+		run(ctx, c, plan.Actions, indexes, concurrency, result.Results, opts)
+
+		for _, i := range indexes {
+			if a, ok := plan.Actions[i].(*CreateUserAction); ok && userNotCreated(result.Results[i].Err) {
+				failedUsers[*a.Request.AccountName] = true
+			}
+		}
+	}
+
+	var errs []error
+	for _, r := range result.Failed() {
+		errs = append(errs, fmt.Errorf("%s: %w", r.Action.Resource(), r.Err))
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// userNotCreated は CreateUserAction の err がユーザーの作成自体の失敗かを判定する
+// 作成後のアカウントの有効/無効の設定に失敗した場合、ユーザーは存在する
+func userNotCreated(err error) bool {
+	var statusErr *accountStatusError
+	return err != nil && !errors.As(err, &statusErr)
+}
+
+func run(ctx context.Context, c *yno.YNOClient, actions []Action, indexes []int, concurrency int, results []ActionResult, opts []yno.OptionFunc) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for _, i := range indexes {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			r, err := actions[i].apply(ctx, c, opts...)
+			results[i].GeneratedPassword = r.GeneratedPassword
+			results[i].Err = err
+		}()
+	}
+	wg.Wait()
+}
+
+func isUserUpsert(action Action) bool {
+	return strings.HasPrefix(action.Resource(), "user/") && action.Type() != ActionDelete
+}
+
+func isRouterUpdate(action Action) bool {
+	return strings.HasPrefix(action.Resource(), "router/")
+}
+
+func isUserDelete(action Action) bool {
+	return strings.HasPrefix(action.Resource(), "user/") && action.Type() == ActionDelete
+}
+
+// failedDependency は action が作成に失敗したユーザーを割り当てる場合にそのリソース名を返す
+func failedDependency(action Action, failedUsers map[string]bool) string {
+	a, ok := action.(*UpdateRouterAction)
+	if !ok {
+		return ""
+	}
+
+	if i := slices.IndexFunc(a.Request.AssignedUsers, func(user string) bool { return failedUsers[user] }); i >= 0 {
+		return "user/" + a.Request.AssignedUsers[i]
+	}
+
+	return ""
+}
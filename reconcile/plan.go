@@ -0,0 +1,359 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	yno "github.com/murasame29/yno-sdk"
+)
+
+type ActionType string
+
+const (
+	ActionCreate ActionType = "create"
+	ActionUpdate ActionType = "update"
+	ActionDelete ActionType = "delete"
+)
+
+// Action は計画に含まれる1つの変更
+type Action interface {
+	Type() ActionType
+	// Resource: "user/<AccountName>" または "router/<SerialNumber>"
+	Resource() string
+	String() string
+
+	apply(ctx context.Context, c *yno.YNOClient, opts ...yno.OptionFunc) (ActionResult, error)
+}
+
+// CreateUserAction は CreateUser でユーザーを作成する
+type CreateUserAction struct {
+	Request *yno.CreateUserRequest
+	// Enabled: false の場合は作成後に UpdateUser でアカウントを無効にする
+	Enabled *bool
+}
+
+func (a *CreateUserAction) Type() ActionType { return ActionCreate }
+
+func (a *CreateUserAction) Resource() string { return "user/" + *a.Request.AccountName }
+
+func (a *CreateUserAction) String() string {
+	s := "+ " + a.Resource()
+	if len(a.Request.EmailAddressesForNotification) > 0 {
+		s += " emails=" + formatEmails(a.Request.EmailAddressesForNotification)
+	}
+	if a.Enabled != nil {
+		s += fmt.Sprintf(" enabled=%t", *a.Enabled)
+	}
+	return s
+}
+
+func (a *CreateUserAction) apply(ctx context.Context, c *yno.YNOClient, opts ...yno.OptionFunc) (ActionResult, error) {
+	resp, err := c.CreateUser(ctx, a.Request, opts...)
+	if err != nil {
+		return ActionResult{}, err
+	}
+
+	result := ActionResult{GeneratedPassword: resp.Data.AutoGeneratedPassword}
+	if a.Enabled != nil && *a.Enabled != resp.Data.AccountStatus {
+		if _, err := c.UpdateUser(ctx, resp.Data.AccountName, &yno.UpdateUserRequest{AccountStatus: a.Enabled}, opts...); err != nil {
+			return result, &accountStatusError{err: err}
+		}
+	}
+
+	return result, nil
+}
+
+// accountStatusError はユーザーを作成した後、アカウントの有効/無効の設定に失敗したことを示す
+// ユーザーは作成されているため、Apply はそのユーザーを割り当てるルーターの更新を続ける
+type accountStatusError struct {
+	err error
+}
+
+func (e *accountStatusError) Error() string {
+	return "created but failed to set account status: " + e.err.Error()
+}
+
+func (e *accountStatusError) Unwrap() error {
+	return e.err
+}
+
+// UpdateUserAction は UpdateUser でユーザーを更新する
+type UpdateUserAction struct {
+	AccountName string
+	Before      yno.UserResponseUser
+	Request     *yno.UpdateUserRequest
+}
+
+func (a *UpdateUserAction) Type() ActionType { return ActionUpdate }
+
+func (a *UpdateUserAction) Resource() string { return "user/" + a.AccountName }
+
+func (a *UpdateUserAction) String() string {
+	var changes []string
+	if a.Request.AccountStatus != nil {
+		changes = append(changes, fmt.Sprintf("enabled: %t -> %t", a.Before.AccountStatus, *a.Request.AccountStatus))
+	}
+	if a.Request.EmailAddressesForNotification != nil {
+		changes = append(changes, fmt.Sprintf("emails: [%s] -> [%s]",
+			formatEmails(a.Before.EmailAddressesForNotification), formatEmails(a.Request.EmailAddressesForNotification)))
+	}
+
+	return "~ " + a.Resource() + " " + strings.Join(changes, ", ")
+}
+
+func (a *UpdateUserAction) apply(ctx context.Context, c *yno.YNOClient, opts ...yno.OptionFunc) (ActionResult, error) {
+	_, err := c.UpdateUser(ctx, a.AccountName, a.Request, opts...)
+	return ActionResult{}, err
+}
+
+// DeleteUserAction は DeleteUser でユーザーを削除する
+type DeleteUserAction struct {
+	AccountName string
+	Before      yno.UserResponseUser
+}
+
+func (a *DeleteUserAction) Type() ActionType { return ActionDelete }
+
+func (a *DeleteUserAction) Resource() string { return "user/" + a.AccountName }
+
+func (a *DeleteUserAction) String() string { return "- " + a.Resource() }
+
+func (a *DeleteUserAction) apply(ctx context.Context, c *yno.YNOClient, opts ...yno.OptionFunc) (ActionResult, error) {
+	_, err := c.DeleteUser(ctx, a.AccountName, opts...)
+	if yno.IsNotFound(err) {
+		return ActionResult{}, nil
+	}
+	return ActionResult{}, err
+}
+
+// UpdateRouterAction は UpdateRotuer でルーターのラベルと割り当てを更新する
+type UpdateRouterAction struct {
+	SerialNumber string
+	Before       yno.RouterResponseRouter
+	Request      *yno.RouterAssignedObject
+}
+
+func (a *UpdateRouterAction) Type() ActionType { return ActionUpdate }
+
+func (a *UpdateRouterAction) Resource() string { return "router/" + a.SerialNumber }
+
+func (a *UpdateRouterAction) String() string {
+	var changes []string
+	if a.Request.AssignedLabels != nil {
+		changes = append(changes, fmt.Sprintf("labels: %v -> %v", a.Before.AssignedLabels, a.Request.AssignedLabels))
+	}
+	if a.Request.AssignedUsers != nil {
+		changes = append(changes, fmt.Sprintf("users: %v -> %v", a.Before.AssignedUsers, a.Request.AssignedUsers))
+	}
+
+	return "~ " + a.Resource() + " " + strings.Join(changes, ", ")
+}
+
+func (a *UpdateRouterAction) apply(ctx context.Context, c *yno.YNOClient, opts ...yno.OptionFunc) (ActionResult, error) {
+	_, err := c.UpdateRotuer(ctx, a.SerialNumber, a.Request, opts...)
+	return ActionResult{}, err
+}
+
+// Plan は NewPlan が計算した変更の一覧
+// Actions はユーザーの作成・更新、ルーターの更新、ユーザーの削除の順に並ぶ
+type Plan struct {
+	Actions []Action
+}
+
+func (p *Plan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+func (p *Plan) String() string {
+	var b strings.Builder
+	for _, action := range p.Actions {
+		b.WriteString(action.String() + "\n")
+	}
+	return b.String()
+}
+
+type PlanOptions struct {
+	// Prune: true の場合、desired に含まれないユーザーを削除する
+	Prune bool
+}
+
+// NewPlan は current を desired の状態にするための変更を計算する
+func NewPlan(desired *DesiredState, current *State, options *PlanOptions) (*Plan, error) {
+	if options == nil {
+		options = &PlanOptions{}
+	}
+
+	currentUsers := make(map[string]yno.UserResponseUser, len(current.Users))
+	for _, u := range current.Users {
+		currentUsers[u.AccountName] = u
+	}
+
+	currentRouters := make(map[string]yno.RouterResponseRouter, len(current.Routers))
+	for _, r := range current.Routers {
+		currentRouters[r.SerialNumber] = r
+	}
+
+	plan := &Plan{}
+	desiredUsers := map[string]bool{}
+	for _, u := range desired.Users {
+		if u.AccountName == "" {
+			return nil, yno.ValidateErrorRequired{FieldName: "AccountName"}
+		}
+		if desiredUsers[u.AccountName] {
+			return nil, fmt.Errorf("user %s is declared more than once", u.AccountName)
+		}
+		desiredUsers[u.AccountName] = true
+
+		action, err := planUser(u, currentUsers)
+		if err != nil {
+			return nil, fmt.Errorf("user %s: %w", u.AccountName, err)
+		}
+		if action != nil {
+			plan.Actions = append(plan.Actions, action)
+		}
+	}
+
+	var deletes []Action
+	if options.Prune {
+		for _, u := range current.Users {
+			if !desiredUsers[u.AccountName] {
+				deletes = append(deletes, &DeleteUserAction{AccountName: u.AccountName, Before: u})
+			}
+		}
+	}
+
+	desiredRouters := map[string]bool{}
+	for _, r := range desired.Routers {
+		if desiredRouters[r.SerialNumber] {
+			return nil, fmt.Errorf("router %s is declared more than once", r.SerialNumber)
+		}
+		desiredRouters[r.SerialNumber] = true
+
+		for _, user := range r.Users {
+			_, exists := currentUsers[user]
+			if !desiredUsers[user] && (!exists || options.Prune) {
+				return nil, fmt.Errorf("router %s: user %s is neither declared nor kept", r.SerialNumber, user)
+			}
+		}
+
+		action, err := planRouter(r, currentRouters)
+		if err != nil {
+			return nil, fmt.Errorf("router %s: %w", r.SerialNumber, err)
+		}
+		if action != nil {
+			plan.Actions = append(plan.Actions, action)
+		}
+	}
+
+	plan.Actions = append(plan.Actions, deletes...)
+	return plan, nil
+}
+
+func planUser(u User, current map[string]yno.UserResponseUser) (Action, error) {
+	emails := emailRequest(u.EmailAddresses)
+
+	before, exists := current[u.AccountName]
+	if !exists {
+		request := &yno.CreateUserRequest{
+			AccountName:                   yno.Ptr(u.AccountName),
+			Password:                      u.Password,
+			EmailAddressesForNotification: emails,
+		}
+		if u.Password == nil {
+			request.AutoGeneratePassword = yno.Ptr(true)
+		}
+
+		if err := request.Validate(); err != nil {
+			return nil, err
+		}
+
+		return &CreateUserAction{Request: request, Enabled: u.Enabled}, nil
+	}
+
+	request := &yno.UpdateUserRequest{}
+	if u.Enabled != nil && *u.Enabled != before.AccountStatus {
+		request.AccountStatus = u.Enabled
+	}
+
+	if u.EmailAddresses != nil && !slices.Equal(emailKeys(emails), emailKeys(before.EmailAddressesForNotification)) {
+		// UpdateUser は空のリストを送れないため、通知先を全て消すことはできない
+		if len(emails) == 0 {
+			return nil, fmt.Errorf("%w: email addresses", yno.ErrEmptyAssignment)
+		}
+		request.EmailAddressesForNotification = emails
+	}
+
+	if request.AccountStatus == nil && request.EmailAddressesForNotification == nil {
+		return nil, nil
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &UpdateUserAction{AccountName: u.AccountName, Before: before, Request: request}, nil
+}
+
+func planRouter(r Router, current map[string]yno.RouterResponseRouter) (Action, error) {
+	before, ok := current[r.SerialNumber]
+	if !ok {
+		return nil, yno.ErrRouterNotFound
+	}
+
+	request := &yno.RouterAssignedObject{}
+	if r.Labels != nil && !sameSet(r.Labels, before.AssignedLabels) {
+		request.AssignedLabels = slices.Clone(r.Labels)
+	}
+	if r.Users != nil && !sameSet(r.Users, before.AssignedUsers) {
+		request.AssignedUsers = slices.Clone(r.Users)
+	}
+
+	if request.AssignedLabels == nil && request.AssignedUsers == nil {
+		return nil, nil
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", yno.ErrEmptyAssignment, err)
+	}
+
+	return &UpdateRouterAction{SerialNumber: r.SerialNumber, Before: before, Request: request}, nil
+}
+
+func sameSet(a, b []string) bool {
+	a, b = slices.Sorted(slices.Values(a)), slices.Sorted(slices.Values(b))
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
+
+// emailKeys は比較のためにメールアドレスを "address:format" の昇順のリストにする
+func emailKeys(emails []yno.EmailAddressesForNotification) []string {
+	keys := make([]string, 0, len(emails))
+	for _, e := range emails {
+		keys = append(keys, emailKey(e))
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func emailKey(e yno.EmailAddressesForNotification) string {
+	var address string
+	if e.EmailAddress != nil {
+		address = *e.EmailAddress
+	}
+
+	format := yno.FormatOfAlarmNotificationEmailBodyText
+	if e.FormatOfAlarmNotificationEmailBody != nil {
+		format = *e.FormatOfAlarmNotificationEmailBody
+	}
+
+	return address + ":" + string(format)
+}
+
+func formatEmails(emails []yno.EmailAddressesForNotification) string {
+	keys := make([]string, 0, len(emails))
+	for _, e := range emails {
+		keys = append(keys, emailKey(e))
+	}
+	return strings.Join(keys, ",")
+}
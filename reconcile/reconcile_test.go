@@ -0,0 +1,324 @@
+package reconcile_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/murasame29/yno-sdk"
+	"github.com/murasame29/yno-sdk/reconcile"
+	"github.com/murasame29/yno-sdk/ynotest"
+)
+
+func email(address string) []yno.EmailAddressesForNotification {
+	return []yno.EmailAddressesForNotification{{
+		EmailAddress:                       yno.Ptr(address),
+		FormatOfAlarmNotificationEmailBody: yno.Ptr(yno.FormatOfAlarmNotificationEmailBodyText),
+	}}
+}
+
+func resources(plan *reconcile.Plan) []string {
+	var resources []string
+	for _, action := range plan.Actions {
+		resources = append(resources, string(action.Type())+" "+action.Resource())
+	}
+	return resources
+}
+
+func TestNewPlan(t *testing.T) {
+	current := &reconcile.State{
+		Users: []yno.UserResponseUser{
+			{AccountName: "alice", AccountStatus: true, EmailAddressesForNotification: email("alice@example.com")},
+			{AccountName: "bob", AccountStatus: true},
+			{AccountName: "old", AccountStatus: true},
+		},
+		Routers: []yno.RouterResponseRouter{
+			{SerialNumber: "S1", RouterAssignedObject: yno.RouterAssignedObject{AssignedUsers: []string{"alice", "old"}}},
+			{SerialNumber: "S2", RouterAssignedObject: yno.RouterAssignedObject{AssignedLabels: []string{"tokyo"}}},
+		},
+	}
+
+	desired := &reconcile.DesiredState{
+		Users: []reconcile.User{
+			{AccountName: "alice", EmailAddresses: []reconcile.Email{{Address: "alice@example.com"}}},
+			{AccountName: "bob", Enabled: yno.Ptr(false)},
+			{AccountName: "carol"},
+		},
+		Routers: []reconcile.Router{
+			{SerialNumber: "S1", Users: []string{"alice", "carol"}},
+			{SerialNumber: "S2", Labels: []string{"tokyo"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		options *reconcile.PlanOptions
+		want    []string
+	}{
+		{
+			name: "without prune",
+			want: []string{"update user/bob", "create user/carol", "update router/S1"},
+		},
+		{
+			name:    "prune deletes undeclared users after router updates",
+			options: &reconcile.PlanOptions{Prune: true},
+			want:    []string{"update user/bob", "create user/carol", "update router/S1", "delete user/old"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := reconcile.NewPlan(desired, current, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := resources(plan); !slices.Equal(got, tt.want) {
+				t.Errorf("NewPlan() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPlanErrors(t *testing.T) {
+	current := &reconcile.State{
+		Users: []yno.UserResponseUser{
+			{AccountName: "alice", EmailAddressesForNotification: email("alice@example.com")},
+			{AccountName: "old"},
+		},
+		Routers: []yno.RouterResponseRouter{
+			{SerialNumber: "S1", RouterAssignedObject: yno.RouterAssignedObject{AssignedLabels: []string{"tokyo"}}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		desired *reconcile.DesiredState
+		options *reconcile.PlanOptions
+		wantErr error
+	}{
+		{
+			name:    "router that does not exist",
+			desired: &reconcile.DesiredState{Routers: []reconcile.Router{{SerialNumber: "S9", Labels: []string{"a"}}}},
+			wantErr: yno.ErrRouterNotFound,
+		},
+		{
+			name:    "emptying labels",
+			desired: &reconcile.DesiredState{Routers: []reconcile.Router{{SerialNumber: "S1", Labels: []string{}}}},
+			wantErr: yno.ErrEmptyAssignment,
+		},
+		{
+			name:    "emptying email addresses",
+			desired: &reconcile.DesiredState{Users: []reconcile.User{{AccountName: "alice", EmailAddresses: []reconcile.Email{}}}},
+			wantErr: yno.ErrEmptyAssignment,
+		},
+		{
+			name:    "assigning a user removed by prune",
+			desired: &reconcile.DesiredState{Routers: []reconcile.Router{{SerialNumber: "S1", Users: []string{"old"}}}},
+			options: &reconcile.PlanOptions{Prune: true},
+		},
+		{
+			name:    "user declared twice",
+			desired: &reconcile.DesiredState{Users: []reconcile.User{{AccountName: "bob"}, {AccountName: "bob"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := reconcile.NewPlan(tt.desired, current, tt.options)
+			if err == nil {
+				t.Fatal("NewPlan() succeeded")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewPlan() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func newServer(t *testing.T) (*ynotest.Server, *yno.YNOClient) {
+	t.Helper()
+
+	s := ynotest.NewServer(
+		ynotest.WithUsers(yno.UserResponseUser{AccountName: "old", AccountStatus: true}),
+		ynotest.WithRouters(
+			yno.RouterResponseRouter{SerialNumber: "S1", RouterAssignedObject: yno.RouterAssignedObject{AssignedUsers: []string{"old"}}},
+			yno.RouterResponseRouter{SerialNumber: "S2", RouterAssignedObject: yno.RouterAssignedObject{AssignedLabels: []string{"tokyo"}}},
+		),
+	)
+	t.Cleanup(s.Close)
+
+	c, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s, c
+}
+
+func plan(t *testing.T, c *yno.YNOClient, desired *reconcile.DesiredState, options *reconcile.PlanOptions) *reconcile.Plan {
+	t.Helper()
+
+	current, err := reconcile.Fetch(context.Background(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := reconcile.NewPlan(desired, current, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func TestApply(t *testing.T) {
+	s, c := newServer(t)
+
+	desired := &reconcile.DesiredState{
+		Users: []reconcile.User{{AccountName: "alice", Enabled: yno.Ptr(false)}},
+		Routers: []reconcile.Router{
+			{SerialNumber: "S1", Users: []string{"alice"}},
+			{SerialNumber: "S2", Labels: []string{"osaka"}},
+		},
+	}
+
+	result, err := reconcile.Apply(context.Background(), c, plan(t, c, desired, &reconcile.PlanOptions{Prune: true}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Results) != 4 || result.Results[0].GeneratedPassword == "" {
+		t.Errorf("Apply() = %+v, want 4 results with a generated password for alice", result.Results)
+	}
+
+	if u, ok := s.User("alice"); !ok || u.AccountStatus {
+		t.Errorf("User(alice) = %+v, %t, want a disabled user", u, ok)
+	}
+	if _, ok := s.User("old"); ok {
+		t.Error("User(old) was not pruned")
+	}
+	if router, _ := s.Router("S1"); !slices.Equal(router.AssignedUsers, []string{"alice"}) {
+		t.Errorf("S1 AssignedUsers = %q, want [alice]", router.AssignedUsers)
+	}
+	if router, _ := s.Router("S2"); !slices.Equal(router.AssignedLabels, []string{"osaka"}) {
+		t.Errorf("S2 AssignedLabels = %q, want [osaka]", router.AssignedLabels)
+	}
+
+	// 反映後は差分がない
+	if p := plan(t, c, desired, &reconcile.PlanOptions{Prune: true}); !p.Empty() {
+		t.Errorf("plan after Apply = %q, want empty", resources(p))
+	}
+}
+
+func TestApplySkipsRoutersOfUsersNotCreated(t *testing.T) {
+	s, c := newServer(t)
+
+	desired := &reconcile.DesiredState{
+		Users: []reconcile.User{{AccountName: "alice"}},
+		Routers: []reconcile.Router{
+			{SerialNumber: "S1", Users: []string{"old", "alice"}},
+			{SerialNumber: "S2", Labels: []string{"osaka"}},
+		},
+	}
+	p := plan(t, c, desired, nil)
+
+	s.InjectFault(ynotest.Fault{Method: http.MethodPost, PathPrefix: "/users", StatusCode: http.StatusInternalServerError, Count: 1})
+
+	result, err := reconcile.Apply(context.Background(), c, p, nil)
+	if !errors.Is(err, reconcile.ErrSkipped) {
+		t.Fatalf("Apply() error = %v, want %v", err, reconcile.ErrSkipped)
+	}
+
+	got := map[string]error{}
+	for _, r := range result.Results {
+		got[r.Action.Resource()] = r.Err
+	}
+
+	if err := got["user/alice"]; err == nil || errors.Is(err, reconcile.ErrSkipped) {
+		t.Errorf("user/alice error = %v, want the create error", err)
+	}
+	if err := got["router/S1"]; !errors.Is(err, reconcile.ErrSkipped) {
+		t.Errorf("router/S1 error = %v, want %v", err, reconcile.ErrSkipped)
+	}
+	if err := got["router/S2"]; err != nil {
+		t.Errorf("router/S2 error = %v, want nil", err)
+	}
+
+	if router, _ := s.Router("S1"); !slices.Equal(router.AssignedUsers, []string{"old"}) {
+		t.Errorf("S1 AssignedUsers = %q, want [old]", router.AssignedUsers)
+	}
+}
+
+func TestApplyContinuesWhenOnlyAccountStatusFailed(t *testing.T) {
+	s, c := newServer(t)
+
+	desired := &reconcile.DesiredState{
+		Users:   []reconcile.User{{AccountName: "alice", Enabled: yno.Ptr(false)}},
+		Routers: []reconcile.Router{{SerialNumber: "S1", Users: []string{"alice"}}},
+	}
+	p := plan(t, c, desired, nil)
+
+	// 作成は成功し、作成後のアカウントの無効化のみが失敗する
+	s.InjectFault(ynotest.Fault{Method: http.MethodPost, PathPrefix: "/users/alice", StatusCode: http.StatusInternalServerError, Count: 1})
+
+	result, err := reconcile.Apply(context.Background(), c, p, nil)
+	if err == nil || errors.Is(err, reconcile.ErrSkipped) {
+		t.Fatalf("Apply() error = %v, want the account status error without skips", err)
+	}
+
+	if result.Results[0].Err == nil {
+		t.Error("user/alice succeeded, want the account status error")
+	}
+	if result.Results[1].Err != nil {
+		t.Errorf("router/S1 error = %v, want nil", result.Results[1].Err)
+	}
+
+	if _, ok := s.User("alice"); !ok {
+		t.Error("User(alice) was not created")
+	}
+	if router, _ := s.Router("S1"); !slices.Equal(router.AssignedUsers, []string{"alice"}) {
+		t.Errorf("S1 AssignedUsers = %q, want [alice]", router.AssignedUsers)
+	}
+}
+
+func TestApplyConcurrency(t *testing.T) {
+	var routers []yno.RouterResponseRouter
+	var desired reconcile.DesiredState
+	for _, serialNumber := range []string{"S1", "S2", "S3", "S4"} {
+		routers = append(routers, yno.RouterResponseRouter{SerialNumber: serialNumber})
+		desired.Routers = append(desired.Routers, reconcile.Router{SerialNumber: serialNumber, Labels: []string{"tokyo"}})
+	}
+
+	s := ynotest.NewServer(ynotest.WithRouters(routers...))
+	defer s.Close()
+
+	c, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := plan(t, c, &desired, nil)
+
+	const latency = 100 * time.Millisecond
+	s.InjectFault(ynotest.Fault{Method: http.MethodPut, PathPrefix: "/routers/", Latency: latency})
+
+	start := time.Now()
+	if _, err := reconcile.Apply(context.Background(), c, p, &reconcile.ApplyOptions{Concurrency: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 4件を2件ずつ実行するため、少なくとも2回分の遅延がかかる
+	if elapsed := time.Since(start); elapsed < 2*latency {
+		t.Errorf("Apply() with Concurrency 2 took %s, want at least %s", elapsed, 2*latency)
+	}
+
+	for _, serialNumber := range []string{"S1", "S2", "S3", "S4"} {
+		if router, _ := s.Router(serialNumber); !slices.Equal(router.AssignedLabels, []string{"tokyo"}) {
+			t.Errorf("%s AssignedLabels = %q, want [tokyo]", serialNumber, router.AssignedLabels)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Package reconcile は宣言的に記述したユーザーとルーターの状態を、YNO管理APIの現在の状態と比較して反映する
+//
+//	current, err := reconcile.Fetch(ctx, client)
+//	plan, err := reconcile.NewPlan(desired, current, nil)
+//	fmt.Print(plan)
+//	result, err := reconcile.Apply(ctx, client, plan, nil)
+package reconcile
+
+import (
+	"context"
+
+	yno "github.com/murasame29/yno-sdk"
+)
+
+// DesiredState は反映したい状態
+type DesiredState struct {
+	Users   []User   `json:"users,omitempty" yaml:"users,omitempty"`
+	Routers []Router `json:"routers,omitempty" yaml:"routers,omitempty"`
+}
+
+// User はユーザーの望ましい状態
+// nil のフィールドは管理せず、現在の値を変更しない
+type User struct {
+	AccountName string `json:"account_name" yaml:"account_name"`
+	// Password: 作成時のパスワード。nil の場合はYNOに自動生成させる。既存のユーザーのパスワードは変更しない
+	Password *string `json:"password,omitempty" yaml:"password,omitempty"`
+	// Enabled: アカウントの有効/無効
+	Enabled *bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// EmailAddresses: 通知先のメールアドレス
+	EmailAddresses []Email `json:"email_addresses,omitempty" yaml:"email_addresses,omitempty"`
+}
+
+type Email struct {
+	Address string `json:"address" yaml:"address"`
+	// Format: JSON または Text。空の場合は Text
+	Format yno.FormatOfAlarmNotificationEmailBody `json:"format,omitempty" yaml:"format,omitempty"`
+}
+
+// Router はルーターの望ましい状態
+// ルーターはAPIから作成・削除できないため、既存のルーターのラベルと割り当てのみを管理する
+// DeviceDescription もAPIから変更できないため扱わない
+type Router struct {
+	SerialNumber string `json:"serial_number" yaml:"serial_number"`
+	// Labels: nil の場合は管理しない
+	Labels []string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// Users: nil の場合は管理しない
+	Users []string `json:"users,omitempty" yaml:"users,omitempty"`
+}
+
+// State はYNO管理APIから取得した現在の状態
+type State struct {
+	Users   []yno.UserResponseUser
+	Routers []yno.RouterResponseRouter
+}
+
+// Fetch は SearchUser と SearchRotuer で現在の状態を全て取得する
+func Fetch(ctx context.Context, c *yno.YNOClient, opts ...yno.OptionFunc) (*State, error) {
+	users, err := yno.Collect(c.AllUsers(ctx, nil, opts...), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	routers, err := yno.Collect(c.AllRouters(ctx, nil, opts...), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &State{Users: users, Routers: routers}, nil
+}
+
+func (e Email) format() yno.FormatOfAlarmNotificationEmailBody {
+	if e.Format == "" {
+		return yno.FormatOfAlarmNotificationEmailBodyText
+	}
+	return e.Format
+}
+
+func emailRequest(emails []Email) []yno.EmailAddressesForNotification {
+	if emails == nil {
+		return nil
+	}
+
+	request := make([]yno.EmailAddressesForNotification, 0, len(emails))
+	for _, e := range emails {
+		request = append(request, yno.EmailAddressesForNotification{
+			EmailAddress:                       yno.Ptr(e.Address),
+			FormatOfAlarmNotificationEmailBody: yno.Ptr(e.format()),
+		})
+	}
+
+	return request
+}
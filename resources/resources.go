@@ -0,0 +1,53 @@
+// Package resources は YNOClient の上に、ユーザー・ルーターの割り当て・タスクを
+// Read/Create/Update/Delete の統一した操作で扱う層を提供する
+//
+// Terraform provider のようにリソース単位で状態を管理する利用者を想定しており、
+// 次の規則に従う
+//   - Read は存在しないリソースに対して ErrNotFound を返す
+//   - Delete は既に存在しないリソースに対しても成功する
+//   - APIで削除できないリソース (ルーターの割り当て、タスク) の Delete は何もせずに成功する
+//   - Import は import ID (AccountName, SerialNumber, TaskId) からリソースを読み込む
+//   - Read の結果は Normalize 済みで、同じ状態であれば常に同じ値になる
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	yno "github.com/murasame29/yno-sdk"
+)
+
+// ErrNotFound はリソースが存在しない場合に返される
+var ErrNotFound = errors.New("resources: not found")
+
+// ErrInvalidImportID は import ID の形式が正しくない場合に返される
+var ErrInvalidImportID = errors.New("resources: invalid import id")
+
+func notFound(kind, id string) error {
+	return fmt.Errorf("%s %q: %w", kind, id, ErrNotFound)
+}
+
+// isNotFound はAPIまたは SDK が返した「存在しない」エラーかを判定する
+func isNotFound(err error) bool {
+	return yno.IsNotFound(err) || errors.Is(err, yno.ErrRouterNotFound) || errors.Is(err, ErrNotFound)
+}
+
+func validateImportID(kind, id string) error {
+	if id == "" || strings.TrimSpace(id) != id || strings.ContainsAny(id, "/ ") {
+		return fmt.Errorf("%w: %s %q", ErrInvalidImportID, kind, id)
+	}
+	return nil
+}
+
+// normalizeStrings は重複を取り除いて昇順に並べる。空の場合は nil を返す
+func normalizeStrings(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	values = slices.Clone(values)
+	slices.Sort(values)
+	return slices.Compact(values)
+}
@@ -0,0 +1,217 @@
+package resources_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/murasame29/yno-sdk"
+	"github.com/murasame29/yno-sdk/resources"
+	"github.com/murasame29/yno-sdk/ynotest"
+)
+
+func newClient(t *testing.T, s *ynotest.Server) *yno.YNOClient {
+	t.Helper()
+
+	c, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestUserLifecycle(t *testing.T) {
+	s := ynotest.NewServer()
+	defer s.Close()
+
+	c := newClient(t, s)
+	ctx := context.Background()
+
+	created, err := resources.CreateUser(ctx, c, &resources.User{
+		AccountName: "alice",
+		Enabled:     false,
+		EmailAddresses: []resources.Email{
+			{Address: "Alice@example.com", Format: "json"},
+			{Address: "alice@example.com"},
+			{Address: "bob@example.com"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created.Enabled || created.GeneratedPassword == "" {
+		t.Errorf("CreateUser() = %+v, want a disabled user with a generated password", created)
+	}
+
+	// 大文字と小文字だけが異なるアドレスは Read と同じ規則でまとめてから送る
+	var request yno.CreateUserRequest
+	for _, r := range s.Requests() {
+		if r.Method == http.MethodPost && r.Path == "/users" {
+			if err := json.Unmarshal(r.Body, &request); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if len(request.EmailAddressesForNotification) != 2 {
+		t.Errorf("CreateUser sent %d email addresses, want 2", len(request.EmailAddressesForNotification))
+	}
+
+	read, err := resources.ReadUser(ctx, c, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantEmails := []resources.Email{
+		{Address: "Alice@example.com", Format: yno.FormatOfAlarmNotificationEmailBodyJson},
+		{Address: "bob@example.com", Format: yno.FormatOfAlarmNotificationEmailBodyText},
+	}
+	if !slices.Equal(read.EmailAddresses, wantEmails) || !slices.Equal(created.EmailAddresses, wantEmails) {
+		t.Errorf("EmailAddresses created = %+v, read = %+v, want %+v", created.EmailAddresses, read.EmailAddresses, wantEmails)
+	}
+
+	updated, err := resources.UpdateUser(ctx, c, &resources.User{
+		AccountName:    "alice",
+		Enabled:        true,
+		EmailAddresses: []resources.Email{{Address: "carol@example.com"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated.Enabled || len(updated.EmailAddresses) != 1 || updated.EmailAddresses[0].Address != "carol@example.com" {
+		t.Errorf("UpdateUser() = %+v", updated)
+	}
+
+	if err := resources.DeleteUser(ctx, c, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := resources.DeleteUser(ctx, c, "alice"); err != nil {
+		t.Errorf("DeleteUser() of a deleted user: %v", err)
+	}
+
+	if _, err := resources.ReadUser(ctx, c, "alice"); !errors.Is(err, resources.ErrNotFound) {
+		t.Errorf("ReadUser() after delete error = %v, want %v", err, resources.ErrNotFound)
+	}
+}
+
+func TestUpdateUserEmptyEmailAddresses(t *testing.T) {
+	s := ynotest.NewServer(ynotest.WithUsers(
+		yno.UserResponseUser{
+			AccountName:   "alice",
+			AccountStatus: true,
+			EmailAddressesForNotification: []yno.EmailAddressesForNotification{
+				{EmailAddress: yno.Ptr("alice@example.com"), FormatOfAlarmNotificationEmailBody: yno.Ptr(yno.FormatOfAlarmNotificationEmailBodyText)},
+			},
+		},
+		yno.UserResponseUser{AccountName: "bob", AccountStatus: true},
+	))
+	defer s.Close()
+
+	c := newClient(t, s)
+	ctx := context.Background()
+
+	_, err := resources.UpdateUser(ctx, c, &resources.User{AccountName: "alice", Enabled: false})
+	if !errors.Is(err, yno.ErrEmptyAssignment) {
+		t.Fatalf("UpdateUser() error = %v, want %v", err, yno.ErrEmptyAssignment)
+	}
+
+	// 検証に失敗した場合は更新を送らない
+	for _, r := range s.Requests() {
+		if r.Path == "/users/alice" {
+			t.Errorf("UpdateUser() sent %s %s", r.Method, r.Path)
+		}
+	}
+	if u, _ := s.User("alice"); !u.AccountStatus {
+		t.Error("UpdateUser() disabled alice despite the validation error")
+	}
+
+	// メールアドレスがないユーザーは空のまま更新できる
+	if _, err := resources.UpdateUser(ctx, c, &resources.User{AccountName: "bob", Enabled: false}); err != nil {
+		t.Errorf("UpdateUser(bob) error = %v", err)
+	}
+
+	if _, err := resources.UpdateUser(ctx, c, &resources.User{AccountName: "carol"}); !errors.Is(err, resources.ErrNotFound) {
+		t.Errorf("UpdateUser(carol) error = %v, want %v", err, resources.ErrNotFound)
+	}
+}
+
+func TestRouterAssignment(t *testing.T) {
+	s := ynotest.NewServer(ynotest.WithRouters(yno.RouterResponseRouter{SerialNumber: "S1"}))
+	defer s.Close()
+
+	c := newClient(t, s)
+	ctx := context.Background()
+
+	updated, err := resources.UpdateRouterAssignment(ctx, c, &resources.RouterAssignment{
+		SerialNumber: "S1",
+		Labels:       []string{"tokyo", "hq", "tokyo"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(updated.Labels, []string{"hq", "tokyo"}) {
+		t.Errorf("Labels = %q, want [hq tokyo]", updated.Labels)
+	}
+
+	if _, err := resources.UpdateRouterAssignment(ctx, c, &resources.RouterAssignment{SerialNumber: "S1", Labels: []string{}}); !errors.Is(err, yno.ErrEmptyAssignment) {
+		t.Errorf("UpdateRouterAssignment(empty labels) error = %v, want %v", err, yno.ErrEmptyAssignment)
+	}
+
+	// 削除は何もせずに成功し、割り当ては残る
+	if err := resources.DeleteRouterAssignment(ctx, c, "S1"); err != nil {
+		t.Errorf("DeleteRouterAssignment() error = %v", err)
+	}
+	if router, _ := s.Router("S1"); !slices.Equal(router.AssignedLabels, []string{"hq", "tokyo"}) {
+		t.Errorf("AssignedLabels after delete = %q", router.AssignedLabels)
+	}
+
+	if _, err := resources.ReadRouterAssignment(ctx, c, "S9"); !errors.Is(err, resources.ErrNotFound) {
+		t.Errorf("ReadRouterAssignment(S9) error = %v, want %v", err, resources.ErrNotFound)
+	}
+
+	if _, err := resources.ImportRouterAssignment(ctx, c, "S1 "); !errors.Is(err, resources.ErrInvalidImportID) {
+		t.Errorf("ImportRouterAssignment(%q) error = %v, want %v", "S1 ", err, resources.ErrInvalidImportID)
+	}
+}
+
+func TestTask(t *testing.T) {
+	s := ynotest.NewServer(ynotest.WithRouters(yno.RouterResponseRouter{SerialNumber: "S1"}, yno.RouterResponseRouter{SerialNumber: "S2"}))
+	defer s.Close()
+
+	c := newClient(t, s)
+	ctx := context.Background()
+
+	created, err := resources.CreateTask(ctx, c, &resources.Task{
+		SerialNumbers: []string{"S2", "S1"},
+		Commands:      []string{"show environment"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.TaskID == "" || !slices.Equal(created.SerialNumbers, []string{"S1", "S2"}) {
+		t.Errorf("CreateTask() = %+v", created)
+	}
+
+	if _, err := c.WaitForTask(ctx, created.TaskID, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := resources.ImportTask(ctx, c, created.TaskID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !read.Completed || len(read.Devices) != 2 || read.Devices[0].SerialNumber != "S1" {
+		t.Errorf("ImportTask() = %+v", read)
+	}
+
+	if err := resources.DeleteTask(ctx, c, created.TaskID); err != nil {
+		t.Errorf("DeleteTask() error = %v", err)
+	}
+
+	if _, err := resources.ReadTask(ctx, c, "task-999"); !errors.Is(err, resources.ErrNotFound) {
+		t.Errorf("ReadTask(task-999) error = %v, want %v", err, resources.ErrNotFound)
+	}
+}
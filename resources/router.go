@@ -0,0 +1,101 @@
+package resources
+
+import (
+	"context"
+	"errors"
+
+	yno "github.com/murasame29/yno-sdk"
+)
+
+// RouterAssignment はルーターに付与するラベルとユーザーのリソース。import ID は SerialNumber
+// ルーター自体はAPIから作成・削除できないため、既存のルーターの AssignedLabels と AssignedUsers を管理する
+type RouterAssignment struct {
+	SerialNumber string
+	// Labels: nil の場合は管理しない。Normalize で昇順に並ぶ
+	Labels []string
+	// Users: nil の場合は管理しない。Normalize で昇順に並ぶ
+	Users []string
+}
+
+// ID は import ID を返す
+func (a *RouterAssignment) ID() string {
+	return a.SerialNumber
+}
+
+// Normalize は Labels と Users から重複を取り除いて昇順に並べる
+func (a *RouterAssignment) Normalize() {
+	a.Labels = normalizeStrings(a.Labels)
+	a.Users = normalizeStrings(a.Users)
+}
+
+func routerAssignmentFromResponse(router yno.RouterResponseRouter) *RouterAssignment {
+	a := &RouterAssignment{
+		SerialNumber: router.SerialNumber,
+		Labels:       router.AssignedLabels,
+		Users:        router.AssignedUsers,
+	}
+
+	a.Normalize()
+	return a
+}
+
+// ReadRouterAssignment はルーターのラベルとユーザーを読み込む。ルーターが存在しない場合は ErrNotFound を返す
+func ReadRouterAssignment(ctx context.Context, c *yno.YNOClient, serialNumber string, opts ...yno.OptionFunc) (*RouterAssignment, error) {
+	router, err := c.GetRouter(ctx, serialNumber, opts...)
+	if errors.Is(err, yno.ErrRouterNotFound) {
+		return nil, notFound("router", serialNumber)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return routerAssignmentFromResponse(*router), nil
+}
+
+// ImportRouterAssignment は SerialNumber を import ID としてルーターのラベルとユーザーを読み込む
+func ImportRouterAssignment(ctx context.Context, c *yno.YNOClient, id string, opts ...yno.OptionFunc) (*RouterAssignment, error) {
+	if err := validateImportID("router", id); err != nil {
+		return nil, err
+	}
+
+	return ReadRouterAssignment(ctx, c, id, opts...)
+}
+
+// CreateRouterAssignment はルーターにラベルとユーザーを設定する
+// ルーターは作成できないため UpdateRouterAssignment と同じ操作になる
+func CreateRouterAssignment(ctx context.Context, c *yno.YNOClient, a *RouterAssignment, opts ...yno.OptionFunc) (*RouterAssignment, error) {
+	return UpdateRouterAssignment(ctx, c, a, opts...)
+}
+
+// UpdateRouterAssignment はルーターのラベルとユーザーを a の内容で置き換え、更新後の状態を返す
+// APIの制約により空のリストには更新できず、その場合は yno.ErrEmptyAssignment を返す
+func UpdateRouterAssignment(ctx context.Context, c *yno.YNOClient, a *RouterAssignment, opts ...yno.OptionFunc) (*RouterAssignment, error) {
+	if (a.Labels != nil && len(a.Labels) == 0) || (a.Users != nil && len(a.Users) == 0) {
+		return nil, yno.ErrEmptyAssignment
+	}
+
+	if a.Labels == nil && a.Users == nil {
+		return ReadRouterAssignment(ctx, c, a.SerialNumber, opts...)
+	}
+
+	requestBody := &yno.RouterAssignedObject{
+		AssignedLabels: normalizeStrings(a.Labels),
+		AssignedUsers:  normalizeStrings(a.Users),
+	}
+
+	responseBody, err := c.UpdateRotuer(ctx, a.SerialNumber, requestBody, opts...)
+	if isNotFound(err) {
+		return nil, notFound("router", a.SerialNumber)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return routerAssignmentFromResponse(responseBody.Data), nil
+}
+
+// DeleteRouterAssignment は何もせずに成功する
+// APIでは AssignedLabels と AssignedUsers を空にできないため、割り当てはルーターに残したまま管理をやめる
+func DeleteRouterAssignment(ctx context.Context, c *yno.YNOClient, serialNumber string, opts ...yno.OptionFunc) error {
+	return nil
+}
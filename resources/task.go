@@ -0,0 +1,103 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	yno "github.com/murasame29/yno-sdk"
+)
+
+// Task はコマンド実行タスクのリソース。import ID は TaskId
+// タスクは作成後に変更できないため Update は提供しない。変更する場合は作り直す
+type Task struct {
+	TaskID string
+	// SerialNumbers, Commands, Timeout: 作成時に指定する。APIから読み込めないため Read では設定されない
+	SerialNumbers []string
+	Commands      []string
+	Timeout       *int
+	// Devices: ルーターごとの実行結果。SerialNumber の昇順に並ぶ
+	Devices []yno.DeviceTaskResult
+	// Completed: 全てのルーターの実行が終了したか
+	Completed bool
+}
+
+// ID は import ID を返す
+func (t *Task) ID() string {
+	return t.TaskID
+}
+
+// ReadTask はタスクの実行結果を読み込む。存在しない場合は ErrNotFound を返す
+func ReadTask(ctx context.Context, c *yno.YNOClient, taskID string, opts ...yno.OptionFunc) (*Task, error) {
+	var devices []yno.DeviceTaskResult
+	for device, err := range c.AllTaskDevices(ctx, taskID, opts...) {
+		if isNotFound(err) {
+			return nil, notFound("task", taskID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+
+	task := &Task{TaskID: taskID, Devices: devices, Completed: len(devices) > 0}
+	for _, device := range devices {
+		if !device.Status.IsTerminal() {
+			task.Completed = false
+		}
+	}
+
+	task.Normalize()
+	return task, nil
+}
+
+// ImportTask は TaskId を import ID としてタスクを読み込む
+func ImportTask(ctx context.Context, c *yno.YNOClient, id string, opts ...yno.OptionFunc) (*Task, error) {
+	if err := validateImportID("task", id); err != nil {
+		return nil, err
+	}
+
+	return ReadTask(ctx, c, id, opts...)
+}
+
+// Normalize は Devices と SerialNumbers をシリアル番号の昇順に並べる
+func (t *Task) Normalize() {
+	slices.SortFunc(t.Devices, func(a, b yno.DeviceTaskResult) int {
+		return strings.Compare(a.SerialNumber, b.SerialNumber)
+	})
+	t.SerialNumbers = normalizeStrings(t.SerialNumbers)
+}
+
+// CreateTask は t.Commands を t.SerialNumbers で実行するタスクを作成し、TaskID を設定したタスクを返す
+// 完了を待たないため、結果は ReadTask または yno.YNOClient.WaitForTask で取得する
+func CreateTask(ctx context.Context, c *yno.YNOClient, t *Task, opts ...yno.OptionFunc) (*Task, error) {
+	requestBody := &yno.CreateTaskRequest{
+		Type:    yno.Ptr(yno.TaskTypeExecuteCommand),
+		Timeout: t.Timeout,
+		Parameters: &yno.TaskParameter{
+			SerialNumbers: t.SerialNumbers,
+			Commands:      t.Commands,
+		},
+	}
+
+	responseBody, err := c.CreateTask(ctx, requestBody, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if responseBody.Data == nil {
+		return nil, errors.New("task id was not returned")
+	}
+
+	created := *t
+	created.TaskID = responseBody.Data.TaskId
+	created.Normalize()
+	return &created, nil
+}
+
+// DeleteTask は何もせずに成功する
+// APIにタスクを削除する操作はないため、タスクはYNOに残したまま管理をやめる
+func DeleteTask(ctx context.Context, c *yno.YNOClient, taskID string, opts ...yno.OptionFunc) error {
+	return nil
+}
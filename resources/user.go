@@ -0,0 +1,202 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	yno "github.com/murasame29/yno-sdk"
+)
+
+// User はユーザーのリソース。import ID は AccountName
+type User struct {
+	AccountName string
+	Enabled     bool
+	// EmailAddresses: 通知先のメールアドレス。Normalize で Address の昇順に並ぶ
+	EmailAddresses []Email
+	// Password: 作成・更新時に設定するパスワード。APIから読み込めないため Read では設定されない
+	// Create で nil の場合はYNOに自動生成させる
+	Password *string
+	// GeneratedPassword: Create でYNOが自動生成したパスワード
+	GeneratedPassword string
+}
+
+type Email struct {
+	Address string
+	Format  yno.FormatOfAlarmNotificationEmailBody
+}
+
+// ID は import ID を返す
+func (u *User) ID() string {
+	return u.AccountName
+}
+
+// Normalize はサーバーが返す値と比較できるように u を正規化する
+// メールアドレスの形式は JSON または Text に揃え、空の場合は Text とする
+// 大文字と小文字だけが異なるメールアドレスは同じアドレスとして最初の1つだけを残す
+// Create と Update も同じ規則で正規化したメールアドレスを送るため、Read の結果と食い違わない
+func (u *User) Normalize() {
+	for i, e := range u.EmailAddresses {
+		u.EmailAddresses[i] = Email{Address: strings.TrimSpace(e.Address), Format: normalizeEmailFormat(e.Format)}
+	}
+
+	slices.SortStableFunc(u.EmailAddresses, func(a, b Email) int {
+		return strings.Compare(strings.ToLower(a.Address), strings.ToLower(b.Address))
+	})
+	u.EmailAddresses = slices.CompactFunc(u.EmailAddresses, func(a, b Email) bool {
+		return strings.EqualFold(a.Address, b.Address)
+	})
+
+	if len(u.EmailAddresses) == 0 {
+		u.EmailAddresses = nil
+	}
+}
+
+func normalizeEmailFormat(format yno.FormatOfAlarmNotificationEmailBody) yno.FormatOfAlarmNotificationEmailBody {
+	switch {
+	case strings.EqualFold(string(format), string(yno.FormatOfAlarmNotificationEmailBodyJson)):
+		return yno.FormatOfAlarmNotificationEmailBodyJson
+	default:
+		return yno.FormatOfAlarmNotificationEmailBodyText
+	}
+}
+
+func userFromResponse(accountName string, enabled bool, emails []yno.EmailAddressesForNotification) *User {
+	u := &User{AccountName: accountName, Enabled: enabled}
+	for _, e := range emails {
+		var email Email
+		if e.EmailAddress != nil {
+			email.Address = *e.EmailAddress
+		}
+		if e.FormatOfAlarmNotificationEmailBody != nil {
+			email.Format = *e.FormatOfAlarmNotificationEmailBody
+		}
+		u.EmailAddresses = append(u.EmailAddresses, email)
+	}
+
+	u.Normalize()
+	return u
+}
+
+// emailRequest は Normalize と同じ規則で正規化したメールアドレスをリクエストの形式にする
+func (u *User) emailRequest() []yno.EmailAddressesForNotification {
+	normalized := User{EmailAddresses: slices.Clone(u.EmailAddresses)}
+	normalized.Normalize()
+
+	var emails []yno.EmailAddressesForNotification
+	for _, e := range normalized.EmailAddresses {
+		emails = append(emails, yno.EmailAddressesForNotification{
+			EmailAddress:                       yno.Ptr(e.Address),
+			FormatOfAlarmNotificationEmailBody: yno.Ptr(normalizeEmailFormat(e.Format)),
+		})
+	}
+
+	return emails
+}
+
+// ReadUser はユーザーを読み込む。存在しない場合は ErrNotFound を返す
+func ReadUser(ctx context.Context, c *yno.YNOClient, accountName string, opts ...yno.OptionFunc) (*User, error) {
+	requestBody := &yno.SearchUserRequest{
+		Query: &yno.SearchUserQuery{
+			Where: &yno.SearchUserWhere{Equal: &yno.SearchUserEqualObject{AccountName: accountName}},
+		},
+	}
+
+	responseBody, err := c.SearchUser(ctx, requestBody, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range responseBody.Data.Users {
+		if u.AccountName == accountName {
+			return userFromResponse(u.AccountName, u.AccountStatus, u.EmailAddressesForNotification), nil
+		}
+	}
+
+	return nil, notFound("user", accountName)
+}
+
+// ImportUser は AccountName を import ID としてユーザーを読み込む
+func ImportUser(ctx context.Context, c *yno.YNOClient, id string, opts ...yno.OptionFunc) (*User, error) {
+	if err := validateImportID("user", id); err != nil {
+		return nil, err
+	}
+
+	return ReadUser(ctx, c, id, opts...)
+}
+
+// CreateUser はユーザーを作成し、作成後の状態を返す
+// Enabled が false の場合は作成後にアカウントを無効にする
+func CreateUser(ctx context.Context, c *yno.YNOClient, u *User, opts ...yno.OptionFunc) (*User, error) {
+	requestBody := &yno.CreateUserRequest{
+		AccountName:                   yno.Ptr(u.AccountName),
+		Password:                      u.Password,
+		EmailAddressesForNotification: u.emailRequest(),
+	}
+	if u.Password == nil {
+		requestBody.AutoGeneratePassword = yno.Ptr(true)
+	}
+
+	responseBody, err := c.CreateUser(ctx, requestBody, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	data := responseBody.Data
+	created := userFromResponse(data.AccountName, data.AccountStatus, data.EmailAddressesForNotification)
+	created.GeneratedPassword = data.AutoGeneratedPassword
+
+	if created.Enabled != u.Enabled {
+		updated, err := c.UpdateUser(ctx, u.AccountName, &yno.UpdateUserRequest{AccountStatus: yno.Ptr(u.Enabled)}, opts...)
+		if err != nil {
+			return created, err
+		}
+		created.Enabled = updated.Data.AccountStatus
+	}
+
+	return created, nil
+}
+
+// UpdateUser は Enabled と EmailAddresses、Password が設定されていればパスワードを更新し、更新後の状態を返す
+// APIの制約により EmailAddresses を空にすることはできないため、
+// EmailAddresses が空で現在のユーザーにメールアドレスがある場合は更新せずに yno.ErrEmptyAssignment を返す
+func UpdateUser(ctx context.Context, c *yno.YNOClient, u *User, opts ...yno.OptionFunc) (*User, error) {
+	if len(u.EmailAddresses) == 0 {
+		current, err := ReadUser(ctx, c, u.AccountName, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(current.EmailAddresses) > 0 {
+			return nil, fmt.Errorf("%w: email addresses of %s", yno.ErrEmptyAssignment, u.AccountName)
+		}
+	}
+
+	requestBody := &yno.UpdateUserRequest{
+		Password:                      u.Password,
+		AccountStatus:                 yno.Ptr(u.Enabled),
+		EmailAddressesForNotification: u.emailRequest(),
+	}
+
+	responseBody, err := c.UpdateUser(ctx, u.AccountName, requestBody, opts...)
+	if isNotFound(err) {
+		return nil, notFound("user", u.AccountName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data := responseBody.Data
+	return userFromResponse(data.AccountName, data.AccountStatus, data.EmailAddressesForNotification), nil
+}
+
+// DeleteUser はユーザーを削除する。既に存在しない場合も成功する
+func DeleteUser(ctx context.Context, c *yno.YNOClient, accountName string, opts ...yno.OptionFunc) error {
+	_, err := c.DeleteUser(ctx, accountName, opts...)
+	if isNotFound(err) {
+		return nil
+	}
+
+	return err
+}
@@ -3,8 +3,6 @@ package yno
 import (
 	"context"
 	"fmt"
-
-	"github.com/murasame29/yno-sdk/client"
 )
 
 type SearchRouterRequest struct {
@@ -15,7 +13,7 @@ type SearchRouterRequest struct {
 
 func (p *SearchRouterRequest) Validate() error {
 	if p.PageSize != nil {
-		if *p.PageSize < 5 || *p.PageSize < 100 {
+		if *p.PageSize < 5 || 100 < *p.PageSize {
 			return ValidateErrorNotMatch{"PageSize", " 5 <= x <= 100"}
 		}
 	}
@@ -102,8 +100,9 @@ type RouterResponseRouter struct {
 }
 
 type UpdateRotuerResponse struct {
-	Meta MetaData             `json:"Meta"`
-	Data RouterResponseRouter `json:"Data"`
+	Meta     MetaData             `json:"Meta"`
+	Data     RouterResponseRouter `json:"Data"`
+	Warnings []Warning            `json:"Warnings,omitempty"`
 }
 
 func (c *YNOClient) SearchRotuer(ctx context.Context, requestBody *SearchRouterRequest, opts ...OptionFunc) (*SearchRouterResponse, error) {
@@ -111,15 +110,16 @@ func (c *YNOClient) SearchRotuer(ctx context.Context, requestBody *SearchRouterR
 		return nil, err
 	}
 
-	var clientOpts []client.Option
-	for _, optFunc := range opts {
-		clientOpts = optFunc(clientOpts)
-	}
+	clientOpts := c.clientOptions(opts)
 
 	var responseBody SearchRouterResponse
 	err := c.client.Post(ctx, "routers/_search", requestBody, &responseBody, clientOpts...)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
+	}
+
+	if err := c.checkResponse(ctx, "SearchRotuer", responseBody.Meta, responseBody.Warnings); err != nil {
+		return responseOnWarning(&responseBody, err), err
 	}
 
 	return &responseBody, nil
@@ -130,15 +130,16 @@ func (c *YNOClient) UpdateRotuer(ctx context.Context, serialNumber string, reque
 		return nil, err
 	}
 
-	var clientOpts []client.Option
-	for _, optFunc := range opts {
-		clientOpts = optFunc(clientOpts)
-	}
+	clientOpts := c.clientOptions(opts)
 
 	var responseBody UpdateRotuerResponse
 	err := c.client.Put(ctx, fmt.Sprintf("routers/%s", serialNumber), requestBody, &responseBody, clientOpts...)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
+	}
+
+	if err := c.checkResponse(ctx, "UpdateRotuer", responseBody.Meta, responseBody.Warnings); err != nil {
+		return responseOnWarning(&responseBody, err), err
 	}
 
 	return &responseBody, nil
@@ -0,0 +1,211 @@
+// Package rtxconfig はヤマハルーター (RTX シリーズ) の設定 (show config の出力) を解析し、
+// 2つの設定の意味的な差分と、一方の設定をもう一方にするためのコマンドを求める
+//
+//	current, err := rtxconfig.Parse(currentText)
+//	desired, err := rtxconfig.Parse(desiredText)
+//	diff := rtxconfig.Compare(current, desired)
+//	fmt.Print(diff)
+//	commands := diff.Commands() // CreateTask の Commands に指定する
+package rtxconfig
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SectionKind は設定の区分
+type SectionKind string
+
+const (
+	// SectionGlobal はどの select にも属さない設定
+	SectionGlobal SectionKind = "global"
+	// SectionPP は pp select N で選択した相手の設定
+	SectionPP SectionKind = "pp"
+	// SectionTunnel は tunnel select N で選択したトンネルの設定
+	SectionTunnel SectionKind = "tunnel"
+	// SectionFilter は ip filter と ipv6 filter の行
+	SectionFilter SectionKind = "filter"
+)
+
+// Statement は設定の1行
+type Statement struct {
+	// Line: 前後の空白を取り除いた行
+	Line string
+	// Key: 同じ設定を表す行で共通の部分。ip filter 200000 や ip lan1 address など
+	// 値を変更した行は Key が同じになる
+	Key string
+}
+
+// Section は区分ごとの設定
+type Section struct {
+	Kind SectionKind
+	// ID: pp や tunnel の番号。SectionGlobal と SectionFilter では空
+	ID         string
+	Statements []Statement
+}
+
+// Name は区分を選択するコマンドを返す。pp select 1 など
+func (s *Section) Name() string {
+	switch s.Kind {
+	case SectionPP, SectionTunnel:
+		return string(s.Kind) + " select " + s.ID
+	case SectionFilter:
+		return "ip filter"
+	}
+	return string(s.Kind)
+}
+
+// Config は解析した設定
+type Config struct {
+	// Sections: global、filter、pp、tunnel の順。pp と tunnel は設定に現れた順
+	Sections []*Section
+}
+
+// Global は SectionGlobal の設定を返す
+func (c *Config) Global() *Section {
+	return c.Section(SectionGlobal, "")
+}
+
+// Section は kind と id の区分を返す。存在しない場合は nil
+func (c *Config) Section(kind SectionKind, id string) *Section {
+	for _, s := range c.Sections {
+		if s.Kind == kind && s.ID == id {
+			return s
+		}
+	}
+	return nil
+}
+
+// PP は pp select id の設定を返す。存在しない場合は nil
+func (c *Config) PP(id string) *Section {
+	return c.Section(SectionPP, id)
+}
+
+// Tunnel は tunnel select id の設定を返す。存在しない場合は nil
+func (c *Config) Tunnel(id string) *Section {
+	return c.Section(SectionTunnel, id)
+}
+
+// Filter は ip filter number の行を返す
+func (c *Config) Filter(number int) (Statement, bool) {
+	return c.lookupFilter("ip filter " + strconv.Itoa(number))
+}
+
+// IPv6Filter は ipv6 filter number の行を返す
+func (c *Config) IPv6Filter(number int) (Statement, bool) {
+	return c.lookupFilter("ipv6 filter " + strconv.Itoa(number))
+}
+
+func (c *Config) lookupFilter(key string) (Statement, bool) {
+	filters := c.Section(SectionFilter, "")
+	if filters == nil {
+		return Statement{}, false
+	}
+
+	for _, st := range filters.Statements {
+		if st.Key == key {
+			return st, true
+		}
+	}
+	return Statement{}, false
+}
+
+// String は設定を show config と同じ形式で返す
+func (c *Config) String() string {
+	var b strings.Builder
+	for _, s := range c.Sections {
+		selectable := s.Kind == SectionPP || s.Kind == SectionTunnel
+		if selectable {
+			b.WriteString(s.Name() + "\n")
+		}
+		for _, st := range s.Statements {
+			if selectable {
+				b.WriteString(" ")
+			}
+			b.WriteString(st.Line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// ParseOutput は CommandResultDetail.Output を解析する
+func ParseOutput(output []string) (*Config, error) {
+	return Parse(strings.Join(output, "\n"))
+}
+
+// Parse は設定を解析する
+// pp select と tunnel select の後の行は、字下げされている間、または
+// 字下げされていない場合は pp select none などで選択を解除するまでその区分に属する
+// コメント行と空行は無視する
+func Parse(text string) (*Config, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	c := &Config{}
+	global := c.section(SectionGlobal, "")
+	filters := c.section(SectionFilter, "")
+
+	var (
+		current  *Section
+		indented bool
+		body     bool
+	)
+	for i, raw := range strings.Split(text, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		isIndented := raw[0] == ' ' || raw[0] == '\t'
+
+		if current != nil {
+			if !body {
+				indented, body = isIndented, true
+			}
+			if indented && !isIndented {
+				current = nil
+			}
+		}
+
+		fields := tokenize(line)
+		if len(fields) >= 2 && (fields[0] == "pp" || fields[0] == "tunnel") && fields[1] == "select" {
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("line %d: invalid %s select: %q", i+1, fields[0], line)
+			}
+
+			current = nil
+			if fields[2] != "none" {
+				current = c.section(SectionKind(fields[0]), fields[2])
+				body = false
+			}
+			continue
+		}
+
+		st := Statement{Line: line, Key: statementKey(fields)}
+		switch {
+		case current != nil:
+			current.Statements = append(current.Statements, st)
+		case fields[0] == "ip" || fields[0] == "ipv6":
+			if len(fields) > 2 && fields[1] == "filter" {
+				filters.Statements = append(filters.Statements, st)
+				continue
+			}
+			global.Statements = append(global.Statements, st)
+		default:
+			global.Statements = append(global.Statements, st)
+		}
+	}
+
+	return c, nil
+}
+
+// section は kind と id の区分を返す。存在しない場合は追加する
+func (c *Config) section(kind SectionKind, id string) *Section {
+	if s := c.Section(kind, id); s != nil {
+		return s
+	}
+
+	s := &Section{Kind: kind, ID: id}
+	c.Sections = append(c.Sections, s)
+	return s
+}
@@ -0,0 +1,144 @@
+package rtxconfig
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Section
+	}{
+		{
+			name: "indented select body",
+			text: `# RTX1210 Rev.14.01.38
+ip lan1 address 192.168.100.1/24
+pp select 1
+ pppoe use lan2
+ pp enable 1
+ip route default gateway pp 1
+tunnel select 1
+ ipsec tunnel 101
+  ipsec sa policy 101 1 esp aes-cbc sha-hmac
+ tunnel enable 1
+ip filter 200000 reject 10.0.0.0/8 * * * *
+`,
+			want: []Section{
+				{Kind: SectionGlobal, Statements: []Statement{
+					{Line: "ip lan1 address 192.168.100.1/24", Key: "ip lan1 address"},
+					{Line: "ip route default gateway pp 1", Key: "ip route default"},
+				}},
+				{Kind: SectionFilter, Statements: []Statement{
+					{Line: "ip filter 200000 reject 10.0.0.0/8 * * * *", Key: "ip filter 200000"},
+				}},
+				{Kind: SectionPP, ID: "1", Statements: []Statement{
+					{Line: "pppoe use lan2", Key: "pppoe use"},
+					{Line: "pp enable 1", Key: "pp enable"},
+				}},
+				{Kind: SectionTunnel, ID: "1", Statements: []Statement{
+					{Line: "ipsec tunnel 101", Key: "ipsec tunnel"},
+					{Line: "ipsec sa policy 101 1 esp aes-cbc sha-hmac", Key: "ipsec sa policy 101"},
+					{Line: "tunnel enable 1", Key: "tunnel enable"},
+				}},
+			},
+		},
+		{
+			name: "unindented select body",
+			text: `pp select 2
+pppoe use lan3
+pp enable 2
+pp select none
+dns server 8.8.8.8
+tunnel select 1
+tunnel enable 1
+tunnel select none
+`,
+			want: []Section{
+				{Kind: SectionGlobal, Statements: []Statement{
+					{Line: "dns server 8.8.8.8", Key: "dns server"},
+				}},
+				{Kind: SectionFilter},
+				{Kind: SectionPP, ID: "2", Statements: []Statement{
+					{Line: "pppoe use lan3", Key: "pppoe use"},
+					{Line: "pp enable 2", Key: "pp enable"},
+				}},
+				{Kind: SectionTunnel, ID: "1", Statements: []Statement{
+					{Line: "tunnel enable 1", Key: "tunnel enable"},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Parse(tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []Section
+			for _, s := range c.Sections {
+				got = append(got, *s)
+			}
+
+			if !slices.EqualFunc(got, tt.want, func(a, b Section) bool {
+				return a.Kind == b.Kind && a.ID == b.ID && slices.Equal(a.Statements, b.Statements)
+			}) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInvalidSelect(t *testing.T) {
+	if _, err := Parse("pp select\n"); err == nil {
+		t.Error("Parse() error = nil, want error")
+	}
+}
+
+func TestConfigFilter(t *testing.T) {
+	c, err := Parse("ip filter 100 pass * * * * *\nipv6 filter 100 reject * * * * *\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if st, ok := c.Filter(100); !ok || st.Line != "ip filter 100 pass * * * * *" {
+		t.Errorf("Filter(100) = %v, %v", st, ok)
+	}
+	if st, ok := c.IPv6Filter(100); !ok || st.Line != "ipv6 filter 100 reject * * * * *" {
+		t.Errorf("IPv6Filter(100) = %v, %v", st, ok)
+	}
+	if _, ok := c.Filter(200); ok {
+		t.Error("Filter(200) found")
+	}
+}
+
+func TestStatementKey(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{line: "ip lan1 address 192.168.100.1/24", want: "ip lan1 address"},
+		{line: "ip filter 200000 reject 10.0.0.0/8 * * * *", want: "ip filter 200000"},
+		{line: "ip filter dynamic 200080 * * ftp", want: "ip filter dynamic 200080"},
+		{line: "ip pp secure filter in 200003 200020", want: "ip pp secure filter in"},
+		{line: "ip route 10.0.0.0/8 gateway tunnel 1", want: "ip route 10.0.0.0/8"},
+		{line: "nat descriptor static 1000 1 192.168.0.2=* tcp 80", want: "nat descriptor static 1000 1"},
+		{line: "ipsec ike pre-shared-key 1 text secret", want: "ipsec ike pre-shared-key 1"},
+		{line: `description lan1 "office lan"`, want: "description lan1"},
+		{line: "pp enable 1", want: "pp enable"},
+		{line: "console character ascii", want: "console character"},
+		{line: "ip filter source-route on", want: "ip filter source-route"},
+		{line: "pp auth accept pap chap", want: "pp auth accept"},
+		{line: "pppoe use lan2", want: "pppoe use"},
+		{line: "clear", want: "clear"},
+	}
+
+	for _, tt := range tests {
+		if got := statementKey(tokenize(tt.line)); got != tt.want {
+			t.Errorf("statementKey(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
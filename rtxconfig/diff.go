@@ -0,0 +1,202 @@
+package rtxconfig
+
+import (
+	"slices"
+	"strings"
+)
+
+// ChangeType は行の変更の種類
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeRemoved ChangeType = "removed"
+	ChangeChanged ChangeType = "changed"
+)
+
+// Change は1行の変更
+type Change struct {
+	Type ChangeType
+	Key  string
+	// Old: 変更前の行。ChangeAdded では空
+	Old string
+	// New: 変更後の行。ChangeRemoved では空
+	New string
+}
+
+func (c Change) String() string {
+	switch c.Type {
+	case ChangeAdded:
+		return "+ " + c.New
+	case ChangeRemoved:
+		return "- " + c.Old
+	}
+	return "~ " + c.Old + " -> " + c.New
+}
+
+// SectionDiff は区分ごとの変更
+type SectionDiff struct {
+	Kind SectionKind
+	ID   string
+	// Added: 区分 (pp select など) 自体が追加された場合 true
+	Added bool
+	// Removed: 区分自体が削除された場合 true
+	Removed bool
+	// Changes: 削除された行 (変更前の順)、変更と追加された行 (変更後の順) の順
+	Changes []Change
+}
+
+// Name は区分を選択するコマンドを返す
+func (d *SectionDiff) Name() string {
+	s := Section{Kind: d.Kind, ID: d.ID}
+	return s.Name()
+}
+
+// Diff は2つの設定の差分
+type Diff struct {
+	Sections []SectionDiff
+}
+
+// Empty は差分がない場合 true を返す
+func (d *Diff) Empty() bool {
+	return len(d.Sections) == 0
+}
+
+func (d *Diff) String() string {
+	var b strings.Builder
+	for _, s := range d.Sections {
+		b.WriteString("[" + s.Name() + "]")
+		switch {
+		case s.Added:
+			b.WriteString(" (added)")
+		case s.Removed:
+			b.WriteString(" (removed)")
+		}
+		b.WriteString("\n")
+
+		for _, change := range s.Changes {
+			b.WriteString("  " + change.String() + "\n")
+		}
+	}
+	return b.String()
+}
+
+// Commands は変更前の設定を変更後の設定にするコマンドを返す
+// 削除された行は no を付けて変更前と逆の順に、変更と追加された行はそのまま変更後の順に並べる
+// pp と tunnel の区分のコマンドは pp select N と pp select none で囲む
+// 選択した状態は同じタスクの中でのみ有効なため、1つのタスクで実行するか、
+// 分割する場合は select を実行し直す yno.RunCommandsOnFleet を使うこと
+func (d *Diff) Commands() []string {
+	var commands []string
+	for _, s := range d.Sections {
+		selectable := s.Kind == SectionPP || s.Kind == SectionTunnel
+		if selectable {
+			commands = append(commands, s.Name())
+		}
+
+		for _, change := range slices.Backward(s.Changes) {
+			if change.Type == ChangeRemoved {
+				commands = append(commands, "no "+change.Old)
+			}
+		}
+		for _, change := range s.Changes {
+			if change.Type != ChangeRemoved {
+				commands = append(commands, change.New)
+			}
+		}
+
+		if selectable {
+			commands = append(commands, string(s.Kind)+" select none")
+		}
+	}
+	return commands
+}
+
+// Compare は old と new の差分を返す
+// 区分ごとに Key が同じ行を比較し、Key が区分内で重複する行 (syslog host など) は行全体で比較する
+func Compare(old, new *Config) *Diff {
+	d := &Diff{}
+
+	sections := slices.Clone(old.Sections)
+	for _, s := range new.Sections {
+		if old.Section(s.Kind, s.ID) == nil {
+			sections = append(sections, s)
+		}
+	}
+
+	for _, kind := range []SectionKind{SectionGlobal, SectionFilter, SectionPP, SectionTunnel} {
+		for _, s := range sections {
+			if s.Kind != kind {
+				continue
+			}
+
+			before, after := old.Section(s.Kind, s.ID), new.Section(s.Kind, s.ID)
+			sd := SectionDiff{
+				Kind:    s.Kind,
+				ID:      s.ID,
+				Added:   before == nil,
+				Removed: after == nil,
+				Changes: compareStatements(statements(before), statements(after)),
+			}
+
+			if len(sd.Changes) > 0 {
+				d.Sections = append(d.Sections, sd)
+			}
+		}
+	}
+
+	return d
+}
+
+func statements(s *Section) []Statement {
+	if s == nil {
+		return nil
+	}
+	return s.Statements
+}
+
+func compareStatements(old, new []Statement) []Change {
+	count := map[string]int{}
+	for _, st := range old {
+		count["old\x00"+st.Key]++
+	}
+	for _, st := range new {
+		count["new\x00"+st.Key]++
+	}
+
+	// 重複する Key は行全体で識別する
+	id := func(st Statement) string {
+		if count["old\x00"+st.Key] > 1 || count["new\x00"+st.Key] > 1 {
+			return "line\x00" + st.Line
+		}
+		return "key\x00" + st.Key
+	}
+
+	oldByID := make(map[string]Statement, len(old))
+	for _, st := range old {
+		oldByID[id(st)] = st
+	}
+	newByID := make(map[string]Statement, len(new))
+	for _, st := range new {
+		newByID[id(st)] = st
+	}
+
+	var changes []Change
+	for _, st := range old {
+		if _, ok := newByID[id(st)]; !ok {
+			changes = append(changes, Change{Type: ChangeRemoved, Key: st.Key, Old: st.Line})
+		}
+	}
+
+	for _, st := range new {
+		before, ok := oldByID[id(st)]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Type: ChangeAdded, Key: st.Key, New: st.Line})
+		case before.Line != st.Line:
+			changes = append(changes, Change{Type: ChangeChanged, Key: st.Key, Old: before.Line, New: st.Line})
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,142 @@
+package rtxconfig
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompareCommands(t *testing.T) {
+	tests := []struct {
+		name    string
+		old     string
+		new     string
+		changes []Change
+		want    []string
+	}{
+		{
+			name: "single value settings change in place",
+			old:  "console character ascii\nip filter source-route on\npp select 1\n pp auth accept pap\n",
+			new:  "console character sjis\nip filter source-route off\npp select 1\n pp auth accept chap\n",
+			want: []string{
+				"console character sjis",
+				"ip filter source-route off",
+				"pp select 1",
+				"pp auth accept chap",
+				"pp select none",
+			},
+		},
+		{
+			name: "duplicate keys compare whole lines",
+			old:  "syslog host 192.168.0.10\nsyslog host 192.168.0.11\n",
+			new:  "syslog host 192.168.0.11\nsyslog host 192.168.0.12\n",
+			changes: []Change{
+				{Type: ChangeRemoved, Key: "syslog host", Old: "syslog host 192.168.0.10"},
+				{Type: ChangeAdded, Key: "syslog host", New: "syslog host 192.168.0.12"},
+			},
+			want: []string{
+				"no syslog host 192.168.0.10",
+				"syslog host 192.168.0.12",
+			},
+		},
+		{
+			name: "filter renumbering",
+			old:  "ip filter 100 pass * * * * *\nip filter 200 reject 10.0.0.0/8 * * * *\n",
+			new:  "ip filter 200 reject 10.0.0.0/8 * * * *\nip filter 300 pass * * * * *\n",
+			changes: []Change{
+				{Type: ChangeRemoved, Key: "ip filter 100", Old: "ip filter 100 pass * * * * *"},
+				{Type: ChangeAdded, Key: "ip filter 300", New: "ip filter 300 pass * * * * *"},
+			},
+			want: []string{
+				"no ip filter 100 pass * * * * *",
+				"ip filter 300 pass * * * * *",
+			},
+		},
+		{
+			name: "no commands in reverse order",
+			old: `pp select 1
+ pppoe use lan2
+ ip pp mtu 1454
+ pp enable 1
+tunnel select 1
+ ipsec tunnel 101
+ tunnel enable 1
+`,
+			new: "pp select 1\n pppoe use lan2\n",
+			want: []string{
+				"pp select 1",
+				"no pp enable 1",
+				"no ip pp mtu 1454",
+				"pp select none",
+				"tunnel select 1",
+				"no tunnel enable 1",
+				"no ipsec tunnel 101",
+				"tunnel select none",
+			},
+		},
+		{
+			name: "added section",
+			old:  "dns server 8.8.8.8\n",
+			new:  "dns server 8.8.8.8\npp select 2\npppoe use lan3\npp enable 2\npp select none\n",
+			want: []string{
+				"pp select 2",
+				"pppoe use lan3",
+				"pp enable 2",
+				"pp select none",
+			},
+		},
+		{
+			name: "no changes",
+			old:  "# Reporting Date: Jan 1\ndns server 8.8.8.8\n",
+			new:  "dns server 8.8.8.8\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old, err := Parse(tt.old)
+			if err != nil {
+				t.Fatal(err)
+			}
+			new, err := Parse(tt.new)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			diff := Compare(old, new)
+			if diff.Empty() != (len(tt.want) == 0) {
+				t.Errorf("Empty() = %v, want %v", diff.Empty(), len(tt.want) == 0)
+			}
+
+			if tt.changes != nil {
+				var changes []Change
+				for _, s := range diff.Sections {
+					changes = append(changes, s.Changes...)
+				}
+				if !slices.Equal(changes, tt.changes) {
+					t.Errorf("changes = %+v, want %+v", changes, tt.changes)
+				}
+			}
+
+			if got := diff.Commands(); !slices.Equal(got, tt.want) {
+				t.Errorf("Commands() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareSectionFlags(t *testing.T) {
+	old, _ := Parse("tunnel select 1\n tunnel enable 1\n")
+	new, _ := Parse("pp select 1\n pp enable 1\n")
+
+	diff := Compare(old, new)
+	if len(diff.Sections) != 2 {
+		t.Fatalf("Sections = %+v", diff.Sections)
+	}
+
+	if s := diff.Sections[0]; s.Kind != SectionPP || !s.Added {
+		t.Errorf("Sections[0] = %+v, want added pp", s)
+	}
+	if s := diff.Sections[1]; s.Kind != SectionTunnel || !s.Removed {
+		t.Errorf("Sections[1] = %+v, want removed tunnel", s)
+	}
+}
@@ -0,0 +1,104 @@
+package rtxconfig
+
+import (
+	"strings"
+	"unicode"
+)
+
+// keyLengths は値の前に識別子が続くため、既定の規則では Key を決められないコマンドの Key の語数
+// 長い接頭辞から順に照合する
+var keyLengths = []struct {
+	prefix string
+	n      int
+}{
+	{"nat descriptor masquerade static", 6},
+	{"nat descriptor static", 5},
+	{"dhcp scope bind", 5},
+	{"pp auth accept", 3},
+	{"pp auth request", 3},
+	{"ipv6 route", 3},
+	{"ip route", 3},
+}
+
+// statementKey は行の Key を返す
+// 既定では値と見なせる語 (数字で始まる、または . / : = * " を含む語) の手前までを Key とする
+// 値の最初の語が整数でその後にも語が続く場合は、その整数を識別子として Key に含める (ip filter 200000 など)
+// secure filter の行は in または out までを Key とする
+// 値と見なせる語がない行は console character ascii のように最後の語を値とする
+func statementKey(fields []string) string {
+	line := strings.Join(fields, " ")
+	for _, k := range keyLengths {
+		if line == k.prefix || strings.HasPrefix(line, k.prefix+" ") {
+			return strings.Join(fields[:min(k.n, len(fields))], " ")
+		}
+	}
+
+	for i, field := range fields {
+		if (field == "in" || field == "out") && i >= 2 && fields[i-1] == "filter" && fields[i-2] == "secure" {
+			return strings.Join(fields[:i+1], " ")
+		}
+	}
+
+	for i, field := range fields {
+		if i == 0 || !isValue(field) {
+			continue
+		}
+
+		if isInteger(field) && i < len(fields)-1 {
+			return strings.Join(fields[:i+1], " ")
+		}
+		return strings.Join(fields[:i], " ")
+	}
+
+	if len(fields) > 1 {
+		return strings.Join(fields[:len(fields)-1], " ")
+	}
+	return line
+}
+
+func isValue(field string) bool {
+	if field == "" {
+		return false
+	}
+	return unicode.IsDigit(rune(field[0])) || strings.ContainsAny(field, `./:=*"`)
+}
+
+func isInteger(field string) bool {
+	for _, r := range field {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return field != ""
+}
+
+// tokenize は行を空白で区切る。" で囲まれた部分は区切らない
+func tokenize(line string) []string {
+	var (
+		fields  []string
+		b       strings.Builder
+		quoted  bool
+		inField bool
+	)
+	for _, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inField = true
+			b.WriteRune(r)
+		case !quoted && (r == ' ' || r == '\t'):
+			if inField {
+				fields = append(fields, b.String())
+				b.Reset()
+				inField = false
+			}
+		default:
+			inField = true
+			b.WriteRune(r)
+		}
+	}
+	if inField {
+		fields = append(fields, b.String())
+	}
+	return fields
+}
@@ -4,8 +4,6 @@ import (
 	"context"
 	"fmt"
 	"strconv"
-
-	"github.com/murasame29/yno-sdk/client"
 )
 
 type CreateTaskRequest struct {
@@ -29,7 +27,7 @@ func (p CreateTaskRequest) Validate() error {
 		return ValidateErrorRequired{"Parameters"}
 	}
 
-	return p.Validate()
+	return p.Parameters.Validate()
 }
 
 type TaskParameter struct {
@@ -42,7 +40,7 @@ func (p TaskParameter) Validate() error {
 		return ValidateErrorRequired{"SerialNumbers"}
 	}
 
-	if len(p.SerialNumbers) == 0 || len(p.SerialNumbers) > 1000 {
+	if len(p.SerialNumbers) == 0 || len(p.SerialNumbers) > MaxTaskSerialNumbers {
 		return ValidateErrorNotMatch{"SerialNumbers", "0 <= x <= 1000"}
 	}
 
@@ -50,7 +48,7 @@ func (p TaskParameter) Validate() error {
 		return ValidateErrorRequired{"Commands"}
 	}
 
-	if len(p.Commands) == 0 || len(p.Commands) > 100 {
+	if len(p.Commands) == 0 || len(p.Commands) > MaxTaskCommands {
 		return ValidateErrorNotMatch{"Commands", "0 <= x <= 100"}
 	}
 
@@ -58,8 +56,9 @@ func (p TaskParameter) Validate() error {
 }
 
 type CreateTaskResponse struct {
-	Meta MetaData                `json:"Meta"`
-	Data *CreateTaskResponseData `json:"Data,omitempty"`
+	Meta     MetaData                `json:"Meta"`
+	Data     *CreateTaskResponseData `json:"Data,omitempty"`
+	Warnings []Warning               `json:"Warnings,omitempty"`
 }
 
 type CreateTaskResponseData struct {
@@ -90,7 +89,7 @@ func (p GetExecuteTaskQuery) Map() map[string]string {
 
 func (p GetExecuteTaskQuery) Validate() error {
 	if p.PageSize != nil {
-		if *p.PageSize < 5 || *p.PageSize < 100 {
+		if *p.PageSize < 5 || 100 < *p.PageSize {
 			return ValidateErrorNotMatch{"PageSize", " 5 <= x <= 100"}
 		}
 	}
@@ -131,14 +130,15 @@ func (c *YNOClient) CreateTask(ctx context.Context, requestBody *CreateTaskReque
 		return nil, err
 	}
 
-	var clientOpts []client.Option
-	for _, optFunc := range opts {
-		clientOpts = optFunc(clientOpts)
-	}
+	clientOpts := c.clientOptions(opts)
 	var responseBody CreateTaskResponse
 	err := c.client.Post(ctx, "tasks", requestBody, &responseBody, clientOpts...)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
+	}
+
+	if err := c.checkResponse(ctx, "CreateTask", responseBody.Meta, responseBody.Warnings); err != nil {
+		return responseOnWarning(&responseBody, err), err
 	}
 
 	return &responseBody, nil
@@ -149,15 +149,16 @@ func (c *YNOClient) GetExecuteTask(ctx context.Context, taskID string, requestQu
 		return nil, err
 	}
 
-	var clientOpts []client.Option
-	for _, optFunc := range opts {
-		clientOpts = optFunc(clientOpts)
-	}
+	clientOpts := c.clientOptions(opts)
 
 	var responseBody ExecuteTaskResponse
 	err := c.client.Get(ctx, fmt.Sprintf("tasks/%s", taskID), requestQuery.Map(), &responseBody, clientOpts...)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
+	}
+
+	if err := c.checkResponse(ctx, "GetExecuteTask", responseBody.Meta, responseBody.Warnings); err != nil {
+		return responseOnWarning(&responseBody, err), err
 	}
 
 	return &responseBody, nil
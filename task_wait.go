@@ -0,0 +1,182 @@
+package yno
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	defaultWaitInterval    = 5 * time.Second
+	defaultWaitMaxInterval = 1 * time.Minute
+	defaultWaitMultiplier  = 1.5
+	defaultTaskTimeout     = 1800 * time.Second
+)
+
+var ErrTaskWaitTimeout = errors.New("task did not complete within timeout")
+
+type WaitForTaskOptions struct {
+	// Interval: 初回のポーリング間隔
+	Interval time.Duration
+	// MaxInterval: バックオフ後のポーリング間隔の上限
+	MaxInterval time.Duration
+	// Multiplier: ポーリング毎に間隔へ掛ける倍率
+	Multiplier float64
+	// Timeout: 待機の上限。CreateTaskRequest.Timeout と同じ値を渡すことを想定
+	Timeout time.Duration
+	// SerialNumbers: 完了を待つシリアル番号。空の場合は取得できたデバイスが全て完了した時点で終了する
+	SerialNumbers []string
+	// OnProgress: ポーリング毎に呼ばれる
+	OnProgress func(TaskProgress)
+}
+
+type TaskProgress struct {
+	TaskID    string
+	Completed int
+	Total     int
+	Elapsed   time.Duration
+	Devices   []DeviceTaskResult
+}
+
+type TaskResult struct {
+	TaskID   string
+	Type     string
+	Devices  []DeviceTaskResult
+	Warnings []Warning
+}
+
+func (r *TaskResult) Device(serialNumber string) (DeviceTaskResult, bool) {
+	for _, device := range r.Devices {
+		if device.SerialNumber == serialNumber {
+			return device, true
+		}
+	}
+
+	return DeviceTaskResult{}, false
+}
+
+func (s ExecuteCommandStatus) IsTerminal() bool {
+	switch s {
+	case ExecuteCommandStatusSuccess, ExecuteCommandStatusFaileed, ExecuteCommandStatusTimeout:
+		return true
+	}
+
+	return false
+}
+
+// WaitForTask はタスクの全デバイスが終了するか Timeout が経過するまで tasks/{id} をポーリングする
+// Timeout が経過した場合はその時点の結果と ErrTaskWaitTimeout を返す
+func (c *YNOClient) WaitForTask(ctx context.Context, taskID string, waitOpts *WaitForTaskOptions, opts ...OptionFunc) (*TaskResult, error) {
+	if waitOpts == nil {
+		waitOpts = &WaitForTaskOptions{}
+	}
+
+	interval := waitOpts.Interval
+	if interval <= 0 {
+		interval = defaultWaitInterval
+	}
+
+	maxInterval := waitOpts.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultWaitMaxInterval
+	}
+
+	multiplier := waitOpts.Multiplier
+	if multiplier < 1 {
+		multiplier = defaultWaitMultiplier
+	}
+
+	timeout := waitOpts.Timeout
+	if timeout <= 0 {
+		timeout = defaultTaskTimeout
+	}
+
+	start := time.Now()
+	deadline := start.Add(timeout)
+
+	for {
+		result, err := c.getTaskResult(ctx, taskID, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		completed, total := taskCompletion(result.Devices, waitOpts.SerialNumbers)
+		if waitOpts.OnProgress != nil {
+			waitOpts.OnProgress(TaskProgress{
+				TaskID:    taskID,
+				Completed: completed,
+				Total:     total,
+				Elapsed:   time.Since(start),
+				Devices:   result.Devices,
+			})
+		}
+
+		if total > 0 && completed == total {
+			return result, nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return result, ErrTaskWaitTimeout
+		}
+
+		wait := min(interval, remaining)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, ctx.Err()
+		case <-timer.C:
+		}
+
+		interval = min(time.Duration(float64(interval)*multiplier), maxInterval)
+	}
+}
+
+func (c *YNOClient) getTaskResult(ctx context.Context, taskID string, opts ...OptionFunc) (*TaskResult, error) {
+	result := &TaskResult{TaskID: taskID}
+
+	requestQuery := &GetExecuteTaskQuery{}
+	for {
+		responseBody, err := c.GetExecuteTask(ctx, taskID, requestQuery, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Type = responseBody.Data.Type
+		result.Devices = append(result.Devices, responseBody.Data.Results.Devices...)
+		result.Warnings = append(result.Warnings, responseBody.Warnings...)
+
+		if responseBody.Data.Results.NextPageToken == "" {
+			return result, nil
+		}
+		requestQuery.PageToken = Ptr(responseBody.Data.Results.NextPageToken)
+	}
+}
+
+func taskCompletion(devices []DeviceTaskResult, serialNumbers []string) (completed, total int) {
+	if len(serialNumbers) == 0 {
+		for _, device := range devices {
+			if device.Status.IsTerminal() {
+				completed++
+			}
+		}
+
+		return completed, len(devices)
+	}
+
+	terminal := make(map[string]bool, len(devices))
+	for _, device := range devices {
+		if device.Status.IsTerminal() {
+			terminal[device.SerialNumber] = true
+		}
+	}
+
+	for _, serialNumber := range serialNumbers {
+		if terminal[serialNumber] {
+			completed++
+		}
+	}
+
+	return completed, len(serialNumbers)
+}
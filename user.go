@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"regexp"
 	"unicode/utf8"
-
-	"github.com/murasame29/yno-sdk/client"
 )
 
 const (
@@ -40,7 +38,7 @@ func (p CreateUserRequest) Validate() error {
 	}
 
 	if p.EmailAddressesForNotification != nil {
-		if len(p.EmailAddressesForNotification) < 20 {
+		if len(p.EmailAddressesForNotification) > 20 {
 			return ValidateErrorNotMatch{"EmailAddress", " len(x) <= 20"}
 		}
 
@@ -63,20 +61,21 @@ func (p EmailAddressesForNotification) Validate() error {
 	if p.EmailAddress == nil {
 		return ValidateErrorRequired{"EmailAddress"}
 	}
-	if len(*p.EmailAddress) < 5 || len(*p.EmailAddress) < 100 {
+	if len(*p.EmailAddress) < 5 || 100 < len(*p.EmailAddress) {
 		return ValidateErrorNotMatch{"EmailAddress", " 5 <= len(x) <= 100"}
 	}
 
 	if p.FormatOfAlarmNotificationEmailBody == nil {
-		return ValidateErrorRequired{"EmailAddress"}
+		return ValidateErrorRequired{"FormatOfAlarmNotificationEmailBody"}
 	}
 
 	return nil
 }
 
 type CreateuserResponse struct {
-	Meta MetaData               `json:"Meta"`
-	Data CreateuserResponseData `json:"Data"`
+	Meta     MetaData               `json:"Meta"`
+	Data     CreateuserResponseData `json:"Data"`
+	Warnings []Warning              `json:"Warnings,omitempty"`
 }
 
 type CreateuserResponseData struct {
@@ -94,7 +93,7 @@ type SearchUserRequest struct {
 
 func (p *SearchUserRequest) Validate() error {
 	if p.PageSize != nil {
-		if *p.PageSize < 5 || *p.PageSize < 100 {
+		if *p.PageSize < 5 || 100 < *p.PageSize {
 			return ValidateErrorNotMatch{"PageSize", " 5 <= x <= 100"}
 		}
 	}
@@ -117,7 +116,8 @@ type SearchUserWhere struct {
 }
 
 type SearchUserEqualObject struct {
-	AccountName   string `json:"AccountName,omitempty"`
+	AccountName string `json:"AccountName,omitempty"`
+	// AccountStatus: "Enabled" または "Disabled"
 	AccountStatus string `json:"AccountStatus,omitempty"`
 }
 
@@ -153,16 +153,12 @@ type UserResponseUser struct {
 type UpdateUserRequest struct {
 	Password                      *string                         `json:"Password,omitempty"`
 	AutoGeneratePassword          *bool                           `json:"AutoGeneratePassword,omitempty"`
-	AccountStatus                 *bool                           `json:"AccountName,omitempty"`
+	AccountStatus                 *bool                           `json:"AccountStatus,omitempty"`
 	EmailAddressesForNotification []EmailAddressesForNotification `json:"EmailAddressesForNotification,omitempty"`
 }
 
 func (p *UpdateUserRequest) Validate() error {
-	if p.Password == nil {
-		if p.AutoGeneratePassword == nil {
-			return ValidateErrorRequired{"Password or AutoGeneratePassword"}
-		}
-	} else {
+	if p.Password != nil {
 		if count := utf8.RuneCountInString(*p.Password); count < 8 || count > 64 {
 			return &ValidateErrorNotMatch{"Password", "8 <= x <= 64"}
 		}
@@ -173,7 +169,7 @@ func (p *UpdateUserRequest) Validate() error {
 	}
 
 	if p.EmailAddressesForNotification != nil {
-		if len(p.EmailAddressesForNotification) < 20 {
+		if len(p.EmailAddressesForNotification) > 20 {
 			return ValidateErrorNotMatch{"EmailAddress", " len(x) <= 20"}
 		}
 
@@ -188,8 +184,9 @@ func (p *UpdateUserRequest) Validate() error {
 }
 
 type UpdateUserResponse struct {
-	Meta MetaData               `json:"Meta"`
-	Data CreateuserResponseData `json:"Data"`
+	Meta     MetaData               `json:"Meta"`
+	Data     UpdateUserResponseData `json:"Data"`
+	Warnings []Warning              `json:"Warnings,omitempty"`
 }
 
 type UpdateUserResponseData struct {
@@ -200,8 +197,9 @@ type UpdateUserResponseData struct {
 }
 
 type DeleteUserResponse struct {
-	Meta MetaData               `json:"Meta"`
-	Data CreateuserResponseData `json:"Data"`
+	Meta     MetaData               `json:"Meta"`
+	Data     DeleteUserResponseData `json:"Data"`
+	Warnings []Warning              `json:"Warnings,omitempty"`
 }
 
 type DeleteUserResponseData struct {
@@ -213,15 +211,16 @@ func (c *YNOClient) CreateUser(ctx context.Context, requestBody *CreateUserReque
 		return nil, err
 	}
 
-	var clientOpts []client.Option
-	for _, optFunc := range opts {
-		clientOpts = optFunc(clientOpts)
-	}
+	clientOpts := c.clientOptions(opts)
 
 	var responseBody CreateuserResponse
 	err := c.client.Post(ctx, "/users", requestBody, &responseBody, clientOpts...)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
+	}
+
+	if err := c.checkResponse(ctx, "CreateUser", responseBody.Meta, responseBody.Warnings); err != nil {
+		return responseOnWarning(&responseBody, err), err
 	}
 
 	return &responseBody, nil
@@ -232,15 +231,16 @@ func (c *YNOClient) SearchUser(ctx context.Context, requestBody *SearchUserReque
 		return nil, err
 	}
 
-	var clientOpts []client.Option
-	for _, optFunc := range opts {
-		clientOpts = optFunc(clientOpts)
-	}
+	clientOpts := c.clientOptions(opts)
 
 	var responseBody SearchUserResponse
 	err := c.client.Post(ctx, "/users/_search", requestBody, &responseBody, clientOpts...)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
+	}
+
+	if err := c.checkResponse(ctx, "SearchUser", responseBody.Meta, responseBody.Warnings); err != nil {
+		return responseOnWarning(&responseBody, err), err
 	}
 
 	return &responseBody, nil
@@ -251,30 +251,32 @@ func (c *YNOClient) UpdateUser(ctx context.Context, accountName string, requestB
 		return nil, err
 	}
 
-	var clientOpts []client.Option
-	for _, optFunc := range opts {
-		clientOpts = optFunc(clientOpts)
-	}
+	clientOpts := c.clientOptions(opts)
 
 	var responseBody UpdateUserResponse
 	err := c.client.Post(ctx, fmt.Sprintf("/users/%s", accountName), requestBody, &responseBody, clientOpts...)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
+	}
+
+	if err := c.checkResponse(ctx, "UpdateUser", responseBody.Meta, responseBody.Warnings); err != nil {
+		return responseOnWarning(&responseBody, err), err
 	}
 
 	return &responseBody, nil
 }
 
 func (c *YNOClient) DeleteUser(ctx context.Context, accountName string, opts ...OptionFunc) (*DeleteUserResponse, error) {
-	var clientOpts []client.Option
-	for _, optFunc := range opts {
-		clientOpts = optFunc(clientOpts)
-	}
+	clientOpts := c.clientOptions(opts)
 
 	var responseBody DeleteUserResponse
 	err := c.client.Delete(ctx, fmt.Sprintf("/users/%s", accountName), &responseBody, clientOpts...)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
+	}
+
+	if err := c.checkResponse(ctx, "DeleteUser", responseBody.Meta, responseBody.Warnings); err != nil {
+		return responseOnWarning(&responseBody, err), err
 	}
 
 	return &responseBody, nil
@@ -0,0 +1,135 @@
+package yno
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/murasame29/yno-sdk/client"
+)
+
+const (
+	// DefaultMngAPIVersion: リクエストに付与する X-Yamaha-YNO-MngAPI-Version の既定値
+	DefaultMngAPIVersion = "1.0"
+	// MinSupportedMngAPIVersion: この SDK が対応するサーバーのAPIバージョンの下限 (この値を含む)
+	MinSupportedMngAPIVersion = "1.0"
+	// MaxSupportedMngAPIVersion: この SDK が対応するサーバーのAPIバージョンの上限 (この値を含まない)
+	MaxSupportedMngAPIVersion = "2.0"
+)
+
+type VersionCheck int
+
+const (
+	// VersionCheckWarn: 対応範囲外のバージョンを検出した場合に YNOClient.OnVersionMismatch を呼ぶ
+	VersionCheckWarn VersionCheck = iota
+	// VersionCheckStrict: 対応範囲外のバージョンを検出した場合に VersionMismatchError を返す
+	VersionCheckStrict
+	// VersionCheckOff: バージョンを検査しない
+	VersionCheckOff
+)
+
+type VersionMismatchError struct {
+	ServerVersion string
+}
+
+// VersionMismatchHandler は VersionCheckWarn でサーバーのAPIバージョンが対応範囲外だった場合に呼ばれる
+// 同じバージョンに対しては、バージョンが変わるまで1度だけ呼ばれる
+type VersionMismatchHandler func(ctx context.Context, err *VersionMismatchError)
+
+func (e *VersionMismatchError) Error() string {
+	return fmt.Sprintf("server MngApiVersion %q is outside the supported range [%s, %s)", e.ServerVersion, MinSupportedMngAPIVersion, MaxSupportedMngAPIVersion)
+}
+
+// ServerAPIVersion は最後に受け取ったレスポンスの Meta.MngApiVersion を返す
+// まだレスポンスを受け取っていない場合は空文字を返す
+func (c *YNOClient) ServerAPIVersion() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.serverAPIVersion
+}
+
+func (c *YNOClient) clientOptions(opts []OptionFunc) []client.Option {
+	var clientOpts []client.Option
+	if c.MngAPIVersion != "" {
+		clientOpts = append(clientOpts, client.WithHeader(mngAPIVersionHeader, c.MngAPIVersion))
+	}
+
+	for _, optFunc := range opts {
+		clientOpts = optFunc(clientOpts)
+	}
+
+	return clientOpts
+}
+
+// checkMeta はレスポンスの Meta からサーバーのAPIバージョンを記録し、対応範囲内かを検査する
+func (c *YNOClient) checkMeta(ctx context.Context, meta MetaData) error {
+	if meta.MngAPIVersion == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	changed := c.serverAPIVersion != meta.MngAPIVersion
+	c.serverAPIVersion = meta.MngAPIVersion
+	c.mu.Unlock()
+
+	if c.VersionCheck == VersionCheckOff || isSupportedMngAPIVersion(meta.MngAPIVersion) {
+		return nil
+	}
+
+	if c.VersionCheck == VersionCheckStrict {
+		return &VersionMismatchError{ServerVersion: meta.MngAPIVersion}
+	}
+
+	// 同じバージョンで何度も警告しない
+	if changed && c.OnVersionMismatch != nil {
+		c.OnVersionMismatch(ctx, &VersionMismatchError{ServerVersion: meta.MngAPIVersion})
+	}
+
+	return nil
+}
+
+func isSupportedMngAPIVersion(version string) bool {
+	v, ok := parseMngAPIVersion(version)
+	if !ok {
+		return false
+	}
+
+	lower, _ := parseMngAPIVersion(MinSupportedMngAPIVersion)
+	upper, _ := parseMngAPIVersion(MaxSupportedMngAPIVersion)
+
+	return compareVersion(v, lower) >= 0 && compareVersion(v, upper) < 0
+}
+
+func parseMngAPIVersion(version string) ([]int, bool) {
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	v := make([]int, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		v = append(v, n)
+	}
+
+	return v, true
+}
+
+func compareVersion(a, b []int) int {
+	for i := range max(len(a), len(b)) {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+
+		if x != y {
+			return x - y
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,66 @@
+package yno_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/murasame29/yno-sdk"
+	"github.com/murasame29/yno-sdk/ynotest"
+)
+
+func TestVersionCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverVersion string
+		check         yno.VersionCheck
+		wantErr       bool
+		wantMismatch  []string
+	}{
+		{name: "warn", serverVersion: "2.1", check: yno.VersionCheckWarn, wantMismatch: []string{"2.1"}},
+		{name: "warn supported", serverVersion: "1.3", check: yno.VersionCheckWarn},
+		{name: "strict", serverVersion: "2.1", check: yno.VersionCheckStrict, wantErr: true},
+		{name: "strict supported", serverVersion: "1.0", check: yno.VersionCheckStrict},
+		{name: "off", serverVersion: "2.1", check: yno.VersionCheckOff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := ynotest.NewServer(ynotest.WithAPIVersion(tt.serverVersion))
+			defer s.Close()
+
+			c, err := s.Client()
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.VersionCheck = tt.check
+
+			var mismatch []string
+			c.OnVersionMismatch = func(ctx context.Context, err *yno.VersionMismatchError) {
+				mismatch = append(mismatch, err.ServerVersion)
+			}
+
+			// 同じバージョンに対しては1度だけ通知される
+			for range 2 {
+				_, err = c.SearchRotuer(context.Background(), &yno.SearchRouterRequest{})
+
+				var mismatchErr *yno.VersionMismatchError
+				if got := errors.As(err, &mismatchErr); got != tt.wantErr {
+					t.Fatalf("SearchRotuer() error = %v, want VersionMismatchError: %t", err, tt.wantErr)
+				}
+				if !tt.wantErr && err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if !slices.Equal(mismatch, tt.wantMismatch) {
+				t.Errorf("OnVersionMismatch got %q, want %q", mismatch, tt.wantMismatch)
+			}
+
+			if got := c.ServerAPIVersion(); got != tt.serverVersion {
+				t.Errorf("ServerAPIVersion() = %q, want %q", got, tt.serverVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package yno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WarningHandler はレスポンスに Warnings が含まれていた場合に呼ばれる
+// op には呼び出したメソッド名 (例: "SearchRotuer") が渡される
+type WarningHandler func(ctx context.Context, op string, warnings []Warning)
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Code, w.Message)
+}
+
+// WarningError は StrictWarnings が有効な場合に Warnings を含むレスポンスに対して返される
+// このエラーを返すメソッドはレスポンスも合わせて返す
+type WarningError struct {
+	Op       string
+	Warnings []Warning
+}
+
+func (e *WarningError) Error() string {
+	messages := make([]string, 0, len(e.Warnings))
+	for _, w := range e.Warnings {
+		messages = append(messages, w.String())
+	}
+
+	return fmt.Sprintf("yno api warning: %s: %s", e.Op, strings.Join(messages, "; "))
+}
+
+// checkResponse はレスポンスの Meta と Warnings を検査する
+func (c *YNOClient) checkResponse(ctx context.Context, op string, meta MetaData, warnings []Warning) error {
+	if err := c.checkMeta(ctx, meta); err != nil {
+		return err
+	}
+
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	if c.OnWarning != nil {
+		c.OnWarning(ctx, op, warnings)
+	}
+
+	if c.StrictWarnings {
+		return &WarningError{Op: op, Warnings: warnings}
+	}
+
+	return nil
+}
+
+// responseOnWarning は err が *WarningError の場合のみ responseBody を返す
+// Meta の検査に失敗した場合など、それ以外のエラーでは nil を返す
+func responseOnWarning[T any](responseBody *T, err error) *T {
+	var warningErr *WarningError
+	if errors.As(err, &warningErr) {
+		return responseBody
+	}
+
+	return nil
+}
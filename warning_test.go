@@ -0,0 +1,37 @@
+package yno_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/murasame29/yno-sdk"
+	"github.com/murasame29/yno-sdk/ynotest"
+)
+
+func TestStrictWarningsReturnsResponse(t *testing.T) {
+	s := ynotest.NewServer(ynotest.WithRouters(yno.RouterResponseRouter{SerialNumber: "S1"}))
+	defer s.Close()
+
+	s.InjectFault(ynotest.Fault{Warnings: []yno.Warning{{Code: "W001", Message: "deprecated"}}})
+
+	c, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.StrictWarnings = true
+
+	resp, err := c.SearchRotuer(context.Background(), &yno.SearchRouterRequest{})
+
+	var warningErr *yno.WarningError
+	if !errors.As(err, &warningErr) {
+		t.Fatalf("SearchRotuer() error = %v, want *yno.WarningError", err)
+	}
+	if warningErr.Op != "SearchRotuer" || len(warningErr.Warnings) != 1 {
+		t.Errorf("WarningError = %+v, want Op SearchRotuer with 1 warning", warningErr)
+	}
+
+	if resp == nil || len(resp.Data.Routers) != 1 || resp.Data.Routers[0].SerialNumber != "S1" {
+		t.Errorf("SearchRotuer() response = %+v, want S1", resp)
+	}
+}
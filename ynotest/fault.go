@@ -0,0 +1,162 @@
+package ynotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	yno "github.com/murasame29/yno-sdk"
+)
+
+// Fault はリクエストに注入する障害
+type Fault struct {
+	// Method: 対象のHTTPメソッド。空の場合は全て
+	Method string
+	// PathPrefix: 対象のパスの接頭辞。空の場合は全て
+	PathPrefix string
+	// Latency: レスポンスを返すまでの遅延
+	Latency time.Duration
+	// StatusCode: 0 以外の場合はこのステータスコードでエラーを返す
+	StatusCode int
+	// RetryAfter: Retry-After ヘッダーの値
+	RetryAfter string
+	// Warnings: 正常なレスポンスに付与する Warnings
+	Warnings []yno.Warning
+	// Count: 障害を注入する回数。0 の場合は ClearFaults を呼ぶまで続く
+	Count int
+}
+
+func (f *Fault) match(r *http.Request) bool {
+	if f.Method != "" && f.Method != r.Method {
+		return false
+	}
+
+	return strings.HasPrefix(r.URL.Path, f.PathPrefix)
+}
+
+// InjectFault は以降のリクエストに障害を注入する
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &f)
+}
+
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+func (s *Server) takeFault(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, f := range s.faults {
+		if !f.match(r) {
+			continue
+		}
+
+		if f.Count > 0 {
+			f.Count--
+			if f.Count == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+
+		return f
+	}
+
+	return nil
+}
+
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		s.mu.Lock()
+		s.requests = append(s.requests, RecordedRequest{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.RawQuery,
+			Body:   body,
+		})
+		s.mu.Unlock()
+
+		var warnings []yno.Warning
+		if f := s.takeFault(r); f != nil {
+			if f.Latency > 0 {
+				select {
+				case <-r.Context().Done():
+					return
+				case <-time.After(f.Latency):
+				}
+			}
+
+			if f.StatusCode != 0 {
+				if f.RetryAfter != "" {
+					w.Header().Set("Retry-After", f.RetryAfter)
+				}
+				s.writeError(w, f.StatusCode, "InjectedFault", http.StatusText(f.StatusCode))
+				return
+			}
+
+			warnings = f.Warnings
+		}
+
+		if s.apiKey != "" && r.Header.Get(apiKeyHeader) != s.apiKey {
+			s.writeError(w, http.StatusUnauthorized, "Unauthorized", "invalid api key")
+			return
+		}
+
+		if len(warnings) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := httptest.NewRecorder()
+		next.ServeHTTP(rec, r)
+
+		respBody := rec.Body.Bytes()
+		if rec.Code < http.StatusMultipleChoices {
+			respBody = withWarnings(respBody, warnings)
+		}
+
+		maps.Copy(w.Header(), rec.Header())
+		w.WriteHeader(rec.Code)
+		w.Write(respBody)
+	})
+}
+
+// withWarnings はJSONのレスポンスボディに Warnings を追加する
+func withWarnings(body []byte, warnings []yno.Warning) []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return body
+	}
+
+	var existing []yno.Warning
+	if raw, ok := fields["Warnings"]; ok {
+		json.Unmarshal(raw, &existing)
+	}
+
+	b, err := json.Marshal(append(existing, warnings...))
+	if err != nil {
+		return body
+	}
+	fields["Warnings"] = b
+
+	merged, err := json.Marshal(fields)
+	if err != nil {
+		return body
+	}
+
+	return merged
+}
@@ -0,0 +1,109 @@
+package ynotest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/murasame29/yno-sdk"
+	"github.com/murasame29/yno-sdk/ynotest"
+)
+
+func TestFaultStatusCode(t *testing.T) {
+	s := ynotest.NewServer()
+	defer s.Close()
+
+	s.InjectFault(ynotest.Fault{
+		Method:     http.MethodPost,
+		PathPrefix: "/routers/",
+		StatusCode: http.StatusServiceUnavailable,
+		RetryAfter: "1",
+		Count:      1,
+	})
+
+	c := newClient(t, s)
+	ctx := context.Background()
+
+	// 対象外のパスには注入されない
+	if _, err := c.SearchUser(ctx, &yno.SearchUserRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := c.SearchRotuer(ctx, &yno.SearchRouterRequest{})
+	wantStatusCode(t, err, http.StatusServiceUnavailable)
+
+	// Count 回注入した後は取り除かれる
+	if _, err := c.SearchRotuer(ctx, &yno.SearchRouterRequest{}); err != nil {
+		t.Errorf("SearchRotuer() after the fault was used up: %v", err)
+	}
+}
+
+func TestFaultUntilCleared(t *testing.T) {
+	s := ynotest.NewServer()
+	defer s.Close()
+
+	s.InjectFault(ynotest.Fault{StatusCode: http.StatusInternalServerError})
+
+	c := newClient(t, s)
+	ctx := context.Background()
+
+	for range 3 {
+		_, err := c.SearchRotuer(ctx, &yno.SearchRouterRequest{})
+		wantStatusCode(t, err, http.StatusInternalServerError)
+	}
+
+	s.ClearFaults()
+
+	if _, err := c.SearchRotuer(ctx, &yno.SearchRouterRequest{}); err != nil {
+		t.Errorf("SearchRotuer() after ClearFaults: %v", err)
+	}
+}
+
+func TestFaultLatency(t *testing.T) {
+	s := ynotest.NewServer()
+	defer s.Close()
+
+	s.InjectFault(ynotest.Fault{Latency: time.Second})
+
+	c := newClient(t, s)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.SearchRotuer(ctx, &yno.SearchRouterRequest{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("SearchRotuer() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestFaultWarnings(t *testing.T) {
+	s := ynotest.NewServer(ynotest.WithRouters(yno.RouterResponseRouter{SerialNumber: "S1"}))
+	defer s.Close()
+
+	warnings := []yno.Warning{{Code: "W001", Message: "deprecated"}}
+	s.InjectFault(ynotest.Fault{Warnings: warnings})
+
+	c := newClient(t, s)
+
+	var got []yno.Warning
+	c.OnWarning = func(ctx context.Context, op string, w []yno.Warning) {
+		got = append(got, w...)
+	}
+
+	resp, err := c.SearchRotuer(context.Background(), &yno.SearchRouterRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(got, warnings) {
+		t.Errorf("OnWarning got %+v, want %+v", got, warnings)
+	}
+
+	// Warnings を追加してもレスポンスの他のフィールドはそのまま返る
+	if len(resp.Data.Routers) != 1 || resp.Data.Routers[0].SerialNumber != "S1" {
+		t.Errorf("SearchRotuer() = %+v, want S1", resp.Data.Routers)
+	}
+}
@@ -0,0 +1,407 @@
+package ynotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	yno "github.com/murasame29/yno-sdk"
+)
+
+func decodeBody(r *http.Request, v any) error {
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+func (s *Server) searchRouters(w http.ResponseWriter, r *http.Request) {
+	var req yno.SearchRouterRequest
+	if err := decodeBody(r, &req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	var routers []yno.RouterResponseRouter
+	for _, serialNumber := range slices.Sorted(maps.Keys(s.routers)) {
+		router := s.routers[serialNumber]
+		if req.Query == nil || req.Query.Where == nil || req.Query.Where.Match(router) {
+			routers = append(routers, router)
+		}
+	}
+	s.mu.Unlock()
+
+	page, next, err := paginate(routers, s.requestPageSize(req.PageSize), deref(req.PageToken))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "InvalidPageToken", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, yno.SearchRouterResponse{
+		Meta: s.meta(),
+		Data: yno.SearchRouterResponseData{
+			NextPageToken: next,
+			Routers:       nonNil(page),
+		},
+	})
+}
+
+func (s *Server) updateRouter(w http.ResponseWriter, r *http.Request) {
+	var req yno.RouterAssignedObject
+	if err := decodeBody(r, &req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+
+	serialNumber := r.PathValue("serialNumber")
+
+	s.mu.Lock()
+	router, ok := s.routers[serialNumber]
+	if ok {
+		if req.AssignedLabels != nil {
+			router.AssignedLabels = slices.Clone(req.AssignedLabels)
+		}
+		if req.AssignedUsers != nil {
+			router.AssignedUsers = slices.Clone(req.AssignedUsers)
+		}
+		s.routers[serialNumber] = router
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "NotFound", fmt.Sprintf("router %s not found", serialNumber))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, yno.UpdateRotuerResponse{
+		Meta: s.meta(),
+		Data: router,
+	})
+}
+
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request) {
+	var req yno.CreateUserRequest
+	if err := decodeBody(r, &req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		s.writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	if _, ok := s.users[*req.AccountName]; ok {
+		s.mu.Unlock()
+		s.writeError(w, http.StatusConflict, "Conflict", fmt.Sprintf("user %s already exists", *req.AccountName))
+		return
+	}
+
+	u := user{
+		UserResponseUser: yno.UserResponseUser{
+			AccountName:                   *req.AccountName,
+			EmailAddressesForNotification: req.EmailAddressesForNotification,
+			AccountStatus:                 true,
+		},
+	}
+
+	var generated string
+	if req.Password != nil {
+		u.password = *req.Password
+	} else if deref(req.AutoGeneratePassword) {
+		generated = s.generatePassword()
+		u.password = generated
+	}
+
+	s.users[u.AccountName] = u
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, yno.CreateuserResponse{
+		Meta: s.meta(),
+		Data: yno.CreateuserResponseData{
+			AccountName:                   u.AccountName,
+			EmailAddressesForNotification: u.EmailAddressesForNotification,
+			AccountStatus:                 u.AccountStatus,
+			AutoGeneratedPassword:         generated,
+		},
+	})
+}
+
+func (s *Server) searchUsers(w http.ResponseWriter, r *http.Request) {
+	var req yno.SearchUserRequest
+	if err := decodeBody(r, &req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	var users []yno.UserResponseUser
+	for _, accountName := range slices.Sorted(maps.Keys(s.users)) {
+		u := s.users[accountName].UserResponseUser
+		if req.Query == nil || req.Query.Where == nil || req.Query.Where.Match(u) {
+			users = append(users, u)
+		}
+	}
+	s.mu.Unlock()
+
+	page, next, err := paginate(users, s.requestPageSize(req.PageSize), deref(req.PageToken))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "InvalidPageToken", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, yno.SearchUserResponse{
+		Meta: s.meta(),
+		Data: yno.SearchUserResponseData{
+			NextPageToken: next,
+			Users:         nonNil(page),
+		},
+	})
+}
+
+func (s *Server) updateUser(w http.ResponseWriter, r *http.Request) {
+	var req yno.UpdateUserRequest
+	if err := decodeBody(r, &req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+
+	accountName := r.PathValue("accountName")
+
+	s.mu.Lock()
+	u, ok := s.users[accountName]
+	var generated string
+	if ok {
+		if req.Password != nil {
+			u.password = *req.Password
+		} else if deref(req.AutoGeneratePassword) {
+			generated = s.generatePassword()
+			u.password = generated
+		}
+		if req.AccountStatus != nil {
+			u.AccountStatus = *req.AccountStatus
+		}
+		if req.EmailAddressesForNotification != nil {
+			u.EmailAddressesForNotification = req.EmailAddressesForNotification
+		}
+		s.users[accountName] = u
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "NotFound", fmt.Sprintf("user %s not found", accountName))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, yno.UpdateUserResponse{
+		Meta: s.meta(),
+		Data: yno.UpdateUserResponseData{
+			AccountName:                   u.AccountName,
+			EmailAddressesForNotification: u.EmailAddressesForNotification,
+			AccountStatus:                 u.AccountStatus,
+			AutoGeneratedPassword:         generated,
+		},
+	})
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, r *http.Request) {
+	accountName := r.PathValue("accountName")
+
+	s.mu.Lock()
+	_, ok := s.users[accountName]
+	delete(s.users, accountName)
+	s.mu.Unlock()
+
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "NotFound", fmt.Sprintf("user %s not found", accountName))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, yno.DeleteUserResponse{
+		Meta: s.meta(),
+		Data: yno.DeleteUserResponseData{Result: "Success"},
+	})
+}
+
+func (s *Server) createTask(w http.ResponseWriter, r *http.Request) {
+	var req yno.CreateTaskRequest
+	if err := decodeBody(r, &req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		s.writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	s.taskSeq++
+	taskID := fmt.Sprintf("task-%d", s.taskSeq)
+	t := &task{taskType: *req.Type}
+	s.tasks[taskID] = t
+	s.mu.Unlock()
+
+	go s.runTask(t, *req.Parameters)
+
+	writeJSON(w, http.StatusOK, yno.CreateTaskResponse{
+		Meta: s.meta(),
+		Data: &yno.CreateTaskResponseData{TaskId: taskID},
+	})
+}
+
+func (s *Server) runTask(t *task, params yno.TaskParameter) {
+	time.Sleep(s.taskDelay)
+
+	devices := make([]yno.DeviceTaskResult, 0, len(params.SerialNumbers))
+	for _, serialNumber := range params.SerialNumbers {
+		s.mu.Lock()
+		_, known := s.routers[serialNumber]
+		s.mu.Unlock()
+
+		device := yno.DeviceTaskResult{
+			Status:       yno.ExecuteCommandStatusSuccess,
+			SerialNumber: serialNumber,
+		}
+
+		if !known {
+			device.Status = yno.ExecuteCommandStatusFaileed
+			devices = append(devices, device)
+			continue
+		}
+
+		for _, command := range params.Commands {
+			output, exitCode := s.commandHandler(serialNumber, command)
+			device.CommandResults = append(device.CommandResults, yno.CommandResultDetail{
+				Output:   output,
+				Command:  command,
+				ExitCode: exitCode,
+			})
+
+			if exitCode != yno.ExitCodeSuccess {
+				device.Status = yno.ExecuteCommandStatusFaileed
+			}
+		}
+
+		devices = append(devices, device)
+	}
+
+	s.mu.Lock()
+	t.devices = devices
+	t.done = true
+	s.mu.Unlock()
+}
+
+func (s *Server) getTask(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("taskID")
+
+	var pageSize *int
+	if v := r.URL.Query().Get("PageSize"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "InvalidRequest", "invalid PageSize")
+			return
+		}
+		pageSize = &n
+	}
+
+	s.mu.Lock()
+	t, ok := s.tasks[taskID]
+	var taskType string
+	var devices []yno.DeviceTaskResult
+	if ok {
+		taskType = t.taskType
+		devices = slices.Clone(t.devices)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "NotFound", fmt.Sprintf("task %s not found", taskID))
+		return
+	}
+
+	page, next, err := paginate(devices, s.requestPageSize(pageSize), r.URL.Query().Get("PageToken"))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "InvalidPageToken", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, yno.ExecuteTaskResponse{
+		Meta: s.meta(),
+		Data: yno.ExecuteTaskData{
+			Type: taskType,
+			Results: yno.ExecuteTaskResults{
+				NextPageToken: next,
+				Devices:       nonNil(page),
+			},
+		},
+	})
+}
+
+func (s *Server) searchDeviceStats(w http.ResponseWriter, r *http.Request) {
+	var req yno.GetDeviceStatsRequest
+	if err := decodeBody(r, &req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		s.writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	var stats []yno.DeviceStatistic
+	for _, stat := range s.stats[statsKey(*req.SerialNumber, *req.Type, req.Parameters)] {
+		if *req.StartTime <= stat.Timestamp && stat.Timestamp <= *req.EndTime {
+			stats = append(stats, stat)
+		}
+	}
+	s.mu.Unlock()
+
+	page, next, err := paginate(stats, s.pageSize, deref(req.SearchAfter))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "InvalidSearchAfter", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, yno.GetDeviceStatsResponse{
+		Meta: s.meta(),
+		Data: yno.GetDeviceStatsResponseData{
+			NextSearchAfter:  next,
+			DeviceStatistics: nonNil(page),
+		},
+	})
+}
+
+func (s *Server) requestPageSize(pageSize *int) int {
+	if pageSize != nil && *pageSize > 0 {
+		return *pageSize
+	}
+
+	return s.pageSize
+}
+
+// generatePassword は s.mu を保持した状態で呼ぶこと
+func (s *Server) generatePassword() string {
+	s.passwdSeq++
+	return fmt.Sprintf("Generated#%08d", s.passwdSeq)
+}
+
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
+
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
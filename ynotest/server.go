@@ -0,0 +1,257 @@
+// Package ynotest はテスト用のインメモリなYNO管理APIサーバーを提供する
+package ynotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	yno "github.com/murasame29/yno-sdk"
+	"github.com/murasame29/yno-sdk/client"
+)
+
+const (
+	defaultPageSize   = 20
+	defaultAPIVersion = "1.0"
+	apiKeyHeader      = "X-Yamaha-YNO-MngAPI-Key"
+)
+
+// CommandHandler はタスクで実行されたコマンドの出力と終了コードを返す
+type CommandHandler func(serialNumber, command string) ([]string, yno.ExitCode)
+
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Body   []byte
+}
+
+type Server struct {
+	*httptest.Server
+
+	apiKey         string
+	apiVersion     string
+	pageSize       int
+	taskDelay      time.Duration
+	commandHandler CommandHandler
+
+	mu        sync.Mutex
+	routers   map[string]yno.RouterResponseRouter
+	users     map[string]user
+	tasks     map[string]*task
+	stats     map[string][]yno.DeviceStatistic
+	faults    []*Fault
+	requests  []RecordedRequest
+	taskSeq   int
+	passwdSeq int
+}
+
+type user struct {
+	yno.UserResponseUser
+	password string
+}
+
+type task struct {
+	taskType string
+	devices  []yno.DeviceTaskResult
+	done     bool
+}
+
+type Option func(*Server)
+
+// WithAPIKey は X-Yamaha-YNO-MngAPI-Key ヘッダーの値を検証する
+func WithAPIKey(apiKey string) Option {
+	return func(s *Server) {
+		s.apiKey = apiKey
+	}
+}
+
+func WithAPIVersion(version string) Option {
+	return func(s *Server) {
+		s.apiVersion = version
+	}
+}
+
+// WithPageSize は PageSize が指定されなかった場合のページサイズを設定する
+func WithPageSize(pageSize int) Option {
+	return func(s *Server) {
+		s.pageSize = pageSize
+	}
+}
+
+// WithTaskDelay はタスクが作成されてから完了するまでの時間を設定する
+func WithTaskDelay(delay time.Duration) Option {
+	return func(s *Server) {
+		s.taskDelay = delay
+	}
+}
+
+func WithCommandHandler(handler CommandHandler) Option {
+	return func(s *Server) {
+		s.commandHandler = handler
+	}
+}
+
+func WithRouters(routers ...yno.RouterResponseRouter) Option {
+	return func(s *Server) {
+		for _, router := range routers {
+			s.routers[router.SerialNumber] = router
+		}
+	}
+}
+
+func WithUsers(users ...yno.UserResponseUser) Option {
+	return func(s *Server) {
+		for _, u := range users {
+			s.users[u.AccountName] = user{UserResponseUser: u}
+		}
+	}
+}
+
+// NewServer はフェイクのYNO管理APIサーバーを起動する。使い終わったら Close を呼ぶこと
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		apiVersion: defaultAPIVersion,
+		pageSize:   defaultPageSize,
+		commandHandler: func(string, string) ([]string, yno.ExitCode) {
+			return []string{}, yno.ExitCodeSuccess
+		},
+		routers: map[string]yno.RouterResponseRouter{},
+		users:   map[string]user{},
+		tasks:   map[string]*task{},
+		stats:   map[string][]yno.DeviceStatistic{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /routers/_search", s.searchRouters)
+	mux.HandleFunc("PUT /routers/{serialNumber}", s.updateRouter)
+	mux.HandleFunc("POST /users", s.createUser)
+	mux.HandleFunc("POST /users/_search", s.searchUsers)
+	mux.HandleFunc("POST /users/{accountName}", s.updateUser)
+	mux.HandleFunc("DELETE /users/{accountName}", s.deleteUser)
+	mux.HandleFunc("POST /tasks", s.createTask)
+	mux.HandleFunc("GET /tasks/{taskID}", s.getTask)
+	mux.HandleFunc("POST /devicestats/_search", s.searchDeviceStats)
+
+	s.Server = httptest.NewServer(s.middleware(mux))
+
+	return s
+}
+
+// Client はこのサーバーに接続する YNOClient を返す
+func (s *Server) Client(opts ...client.Option) (*yno.YNOClient, error) {
+	return yno.NewClient(s.URL, s.apiKey, opts...)
+}
+
+func (s *Server) AddRouter(router yno.RouterResponseRouter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.routers[router.SerialNumber] = router
+}
+
+func (s *Server) Router(serialNumber string) (yno.RouterResponseRouter, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	router, ok := s.routers[serialNumber]
+	return router, ok
+}
+
+func (s *Server) AddUser(u yno.UserResponseUser) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[u.AccountName] = user{UserResponseUser: u}
+}
+
+func (s *Server) User(accountName string) (yno.UserResponseUser, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[accountName]
+	return u.UserResponseUser, ok
+}
+
+// AddDeviceStatistics は GetDeviceStatistic で返す統計情報を追加する
+// params は統計情報の種別に応じたパラメーターで、不要な場合は nil
+func (s *Server) AddDeviceStatistics(serialNumber string, statType yno.DeviceStatType, params yno.Parameter, stats ...yno.DeviceStatistic) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := statsKey(serialNumber, statType, params)
+	s.stats[key] = append(s.stats[key], stats...)
+	slices.SortFunc(s.stats[key], func(a, b yno.DeviceStatistic) int {
+		return a.Timestamp - b.Timestamp
+	})
+}
+
+// Requests はこれまでに受け付けたリクエストを返す
+func (s *Server) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.requests)
+}
+
+func statsKey(serialNumber string, statType yno.DeviceStatType, params yno.Parameter) string {
+	b, _ := json.Marshal(params)
+	return fmt.Sprintf("%s/%s/%s", serialNumber, statType, b)
+}
+
+func (s *Server) meta() yno.MetaData {
+	return yno.MetaData{MngAPIVersion: s.apiVersion}
+}
+
+type errorDetail struct {
+	Code    string `json:"Code"`
+	Message string `json:"Message"`
+}
+
+type errorResponse struct {
+	Meta  yno.MetaData `json:"Meta"`
+	Error errorDetail  `json:"Error"`
+}
+
+func (s *Server) writeError(w http.ResponseWriter, statusCode int, code, message string) {
+	writeJSON(w, statusCode, errorResponse{
+		Meta:  s.meta(),
+		Error: errorDetail{Code: code, Message: message},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+}
+
+// paginate は offset を表すページトークンを使って items を切り出す
+func paginate[T any](items []T, pageSize int, pageToken string) ([]T, string, error) {
+	offset := 0
+	if pageToken != "" {
+		var err error
+		offset, err = strconv.Atoi(pageToken)
+		if err != nil || offset < 0 || offset > len(items) {
+			return nil, "", fmt.Errorf("invalid page token: %q", pageToken)
+		}
+	}
+
+	end := min(offset+pageSize, len(items))
+
+	next := ""
+	if end < len(items) {
+		next = strconv.Itoa(end)
+	}
+
+	return items[offset:end], next, nil
+}
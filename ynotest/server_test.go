@@ -0,0 +1,254 @@
+package ynotest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/murasame29/yno-sdk"
+	"github.com/murasame29/yno-sdk/ynotest"
+)
+
+func newClient(t *testing.T, s *ynotest.Server) *yno.YNOClient {
+	t.Helper()
+
+	c, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func wantStatusCode(t *testing.T, err error, statusCode int) {
+	t.Helper()
+
+	var apiErr *yno.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %v, want *yno.APIError", err)
+	}
+	if apiErr.StatusCode != statusCode {
+		t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, statusCode)
+	}
+}
+
+func TestServerRouters(t *testing.T) {
+	s := ynotest.NewServer(
+		ynotest.WithPageSize(2),
+		ynotest.WithRouters(
+			yno.RouterResponseRouter{SerialNumber: "S3", ModelName: "RTX830"},
+			yno.RouterResponseRouter{SerialNumber: "S1", ModelName: "RTX1300"},
+			yno.RouterResponseRouter{SerialNumber: "S2", ModelName: "RTX1300"},
+		),
+	)
+	defer s.Close()
+
+	c := newClient(t, s)
+	ctx := context.Background()
+
+	routers, err := yno.Collect(c.AllRouters(ctx, nil), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var serialNumbers []string
+	for _, router := range routers {
+		serialNumbers = append(serialNumbers, router.SerialNumber)
+	}
+	if want := []string{"S1", "S2", "S3"}; !slices.Equal(serialNumbers, want) {
+		t.Errorf("AllRouters() = %q, want %q", serialNumbers, want)
+	}
+
+	resp, err := c.SearchRotuer(ctx, &yno.SearchRouterRequest{
+		Query: &yno.SearchRouterQuery{Where: &yno.SearchRouterWhere{
+			Equal: &yno.SearchRouterEqualObject{ModelName: "RTX830"},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Data.Routers) != 1 || resp.Data.Routers[0].SerialNumber != "S3" {
+		t.Errorf("SearchRotuer(ModelName = RTX830) = %+v, want S3", resp.Data.Routers)
+	}
+
+	if _, err := c.UpdateRotuer(ctx, "S1", &yno.RouterAssignedObject{AssignedLabels: []string{"tokyo"}}); err != nil {
+		t.Fatal(err)
+	}
+	if router, _ := s.Router("S1"); !slices.Equal(router.AssignedLabels, []string{"tokyo"}) {
+		t.Errorf("AssignedLabels = %q, want [tokyo]", router.AssignedLabels)
+	}
+
+	_, err = c.UpdateRotuer(ctx, "S9", &yno.RouterAssignedObject{AssignedLabels: []string{"tokyo"}})
+	wantStatusCode(t, err, http.StatusNotFound)
+}
+
+func TestServerUsers(t *testing.T) {
+	s := ynotest.NewServer()
+	defer s.Close()
+
+	c := newClient(t, s)
+	ctx := context.Background()
+
+	created, err := c.CreateUser(ctx, &yno.CreateUserRequest{
+		AccountName:          yno.Ptr("alice"),
+		AutoGeneratePassword: yno.Ptr(true),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Data.AutoGeneratedPassword == "" || !created.Data.AccountStatus {
+		t.Errorf("CreateUser() = %+v, want an enabled user with a generated password", created.Data)
+	}
+
+	_, err = c.CreateUser(ctx, &yno.CreateUserRequest{
+		AccountName: yno.Ptr("alice"),
+		Password:    yno.Ptr("Password#1"),
+	})
+	wantStatusCode(t, err, http.StatusConflict)
+
+	if _, err := c.UpdateUser(ctx, "alice", &yno.UpdateUserRequest{AccountStatus: yno.Ptr(false)}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := c.SearchUser(ctx, &yno.SearchUserRequest{
+		Query: &yno.SearchUserQuery{Where: &yno.SearchUserWhere{
+			Equal: &yno.SearchUserEqualObject{AccountStatus: "Disabled"},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Data.Users) != 1 || resp.Data.Users[0].AccountName != "alice" {
+		t.Errorf("SearchUser(AccountStatus = Disabled) = %+v, want alice", resp.Data.Users)
+	}
+
+	if _, err := c.DeleteUser(ctx, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.User("alice"); ok {
+		t.Error("User(alice) exists after DeleteUser")
+	}
+
+	_, err = c.DeleteUser(ctx, "alice")
+	wantStatusCode(t, err, http.StatusNotFound)
+}
+
+func TestServerTasks(t *testing.T) {
+	s := ynotest.NewServer(
+		ynotest.WithRouters(yno.RouterResponseRouter{SerialNumber: "S1"}, yno.RouterResponseRouter{SerialNumber: "S2"}),
+		ynotest.WithCommandHandler(func(serialNumber, command string) ([]string, yno.ExitCode) {
+			if serialNumber == "S2" {
+				return []string{"Error: Invalid command"}, yno.ExitCodeError
+			}
+			return []string{serialNumber + ": " + command}, yno.ExitCodeSuccess
+		}),
+	)
+	defer s.Close()
+
+	c := newClient(t, s)
+	ctx := context.Background()
+
+	created, err := c.CreateTask(ctx, &yno.CreateTaskRequest{
+		Type: yno.Ptr(yno.TaskTypeExecuteCommand),
+		Parameters: &yno.TaskParameter{
+			SerialNumbers: []string{"S1", "S2", "S9"},
+			Commands:      []string{"show environment"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := c.WaitForTask(ctx, created.Data.TaskId, &yno.WaitForTaskOptions{Interval: 10 * time.Millisecond, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		serialNumber string
+		status       yno.ExecuteCommandStatus
+		output       []string
+	}{
+		{"S1", yno.ExecuteCommandStatusSuccess, []string{"S1: show environment"}},
+		{"S2", yno.ExecuteCommandStatusFaileed, []string{"Error: Invalid command"}},
+		{"S9", yno.ExecuteCommandStatusFaileed, nil},
+	}
+
+	for _, tt := range tests {
+		device, ok := result.Device(tt.serialNumber)
+		if !ok {
+			t.Errorf("Device(%s) not found", tt.serialNumber)
+			continue
+		}
+
+		if device.Status != tt.status {
+			t.Errorf("Device(%s).Status = %s, want %s", tt.serialNumber, device.Status, tt.status)
+		}
+
+		var output []string
+		if len(device.CommandResults) > 0 {
+			output = device.CommandResults[0].Output
+		}
+		if !slices.Equal(output, tt.output) {
+			t.Errorf("Device(%s) output = %q, want %q", tt.serialNumber, output, tt.output)
+		}
+	}
+
+	_, err = c.GetExecuteTask(ctx, "task-999", &yno.GetExecuteTaskQuery{})
+	wantStatusCode(t, err, http.StatusNotFound)
+}
+
+func TestServerDeviceStatistics(t *testing.T) {
+	s := ynotest.NewServer()
+	defer s.Close()
+
+	s.AddDeviceStatistics("S1", yno.DeviceStatTypeMemoryUtilization, nil,
+		yno.DeviceStatistic{Timestamp: 300, Value: 30},
+		yno.DeviceStatistic{Timestamp: 100, Value: 10},
+		yno.DeviceStatistic{Timestamp: 200, Value: 20},
+	)
+
+	c := newClient(t, s)
+
+	resp, err := c.GetDeviceStatistic(context.Background(), &yno.GetDeviceStatsRequest{
+		Type:         yno.Ptr(yno.DeviceStatTypeMemoryUtilization),
+		SerialNumber: yno.Ptr("S1"),
+		StartTime:    yno.Ptr(150),
+		EndTime:      yno.Ptr(300),
+		Statistics:   yno.Ptr(yno.StatisticTypeAverage),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []yno.DeviceStatistic{{Timestamp: 200, Value: 20}, {Timestamp: 300, Value: 30}}
+	if !slices.Equal(resp.Data.DeviceStatistics, want) {
+		t.Errorf("GetDeviceStatistic() = %+v, want %+v", resp.Data.DeviceStatistics, want)
+	}
+}
+
+func TestServerAPIKey(t *testing.T) {
+	s := ynotest.NewServer(ynotest.WithAPIKey("secret"))
+	defer s.Close()
+
+	ctx := context.Background()
+
+	if _, err := newClient(t, s).SearchRotuer(ctx, &yno.SearchRouterRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := yno.NewClient(s.URL, "wrong")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.SearchRotuer(ctx, &yno.SearchRouterRequest{})
+	wantStatusCode(t, err, http.StatusUnauthorized)
+
+	requests := s.Requests()
+	if len(requests) != 2 || requests[0].Method != http.MethodPost || requests[0].Path != "/routers/_search" {
+		t.Errorf("Requests() = %+v, want 2 POST /routers/_search", requests)
+	}
+}